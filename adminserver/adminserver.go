@@ -0,0 +1,232 @@
+// Package adminserver implements a services.Server that exposes the state of the services managed by a
+// services.Runner over HTTP.
+package adminserver
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/setare/go-services"
+	"github.com/setare/go-services/httpserver"
+)
+
+// DefaultAddr is the address the admin server binds to when no address is given (check WithAddr).
+const DefaultAddr = ":9090"
+
+// Server is a services.Server that exposes the following endpoints:
+//
+//   - GET /services: the lifecycle state of each service managed by the Runner (check ServiceStatus);
+//   - GET /healthz: the health report of the Runner (check services.Runner.Health). It responds 503 when it is not
+//     live;
+//   - GET /readyz: the same as /healthz, but it responds 503 when the Runner is not ready;
+//   - POST /shutdown: triggers the graceful shutdown (check WithShutdown). The request must be authenticated with the
+//     `Authorization: Bearer <token>` header.
+type Server struct {
+	*httpserver.Server
+
+	runner   *services.Runner
+	addr     string
+	listener net.Listener
+	token    string
+	shutdown func()
+	handler  http.Handler
+}
+
+// Option configures a Server created by New.
+type Option = func(*Server)
+
+// WithAddr is an Option that sets the address the Server binds to. The default is DefaultAddr.
+func WithAddr(addr string) Option {
+	return func(server *Server) {
+		server.addr = addr
+	}
+}
+
+// WithListener is an Option that makes the Server accept connections from the given listener, instead of binding to
+// an address.
+func WithListener(listener net.Listener) Option {
+	return func(server *Server) {
+		server.listener = listener
+	}
+}
+
+// WithShutdown is an Option that enables the `POST /shutdown` endpoint. Requests authenticated with the given token
+// call the shutdown function, which should start the graceful shutdown (usually, cancelling the ctx given to
+// services.Runner.Run). Without this Option, the endpoint is not available.
+func WithShutdown(token string, shutdown func()) Option {
+	return func(server *Server) {
+		server.token = token
+		server.shutdown = shutdown
+	}
+}
+
+// New creates a new admin Server with the given name that exposes the state of the services managed by the given
+// runner.
+func New(name string, runner *services.Runner, opts ...Option) *Server {
+	server := &Server{
+		runner: runner,
+		addr:   DefaultAddr,
+	}
+	for _, opt := range opts {
+		opt(server)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/services", server.handleServices)
+	mux.HandleFunc("/healthz", server.handleHealth(func(report services.HealthReport) bool {
+		return report.Live
+	}))
+	mux.HandleFunc("/readyz", server.handleHealth(func(report services.HealthReport) bool {
+		return report.Ready
+	}))
+	if server.shutdown != nil {
+		mux.HandleFunc("/shutdown", server.handleShutdown)
+	}
+	server.handler = mux
+
+	httpOpts := make([]httpserver.Option, 0, 1)
+	if server.listener != nil {
+		httpOpts = append(httpOpts, httpserver.WithListener(server.listener))
+	}
+	server.Server = httpserver.New(name, &http.Server{
+		Addr:    server.addr,
+		Handler: mux,
+	}, httpOpts...)
+	return server
+}
+
+// Handler returns the `http.Handler` that serves the admin endpoints. It can be used to mount the endpoints in another
+// server, or to test them with `httptest`.
+func (server *Server) Handler() http.Handler {
+	return server.handler
+}
+
+// ServiceStatus is the JSON representation of the lifecycle state of a service (check services.ServiceStatus).
+type ServiceStatus struct {
+	Name      string     `json:"name"`
+	State     string     `json:"state"`
+	Since     time.Time  `json:"since"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+	Retries   int        `json:"retries"`
+}
+
+// ServiceHealth is the JSON representation of the health of a service (check services.ServiceHealth).
+type ServiceHealth struct {
+	Name          string     `json:"name"`
+	Status        string     `json:"status"`
+	LastError     string     `json:"last_error,omitempty"`
+	Latency       string     `json:"latency"`
+	CheckedAt     time.Time  `json:"checked_at"`
+	LastHealthyAt *time.Time `json:"last_healthy_at,omitempty"`
+}
+
+// HealthReport is the JSON representation of the health report of a Runner (check services.HealthReport).
+type HealthReport struct {
+	Live      bool            `json:"live"`
+	Ready     bool            `json:"ready"`
+	CheckedAt time.Time       `json:"checked_at"`
+	Services  []ServiceHealth `json:"services"`
+}
+
+func (server *Server) handleServices(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	statuses := server.runner.Services()
+	result := make([]ServiceStatus, len(statuses))
+	for idx, status := range statuses {
+		result[idx] = ServiceStatus{
+			Name:      status.Name,
+			State:     status.State.String(),
+			Since:     status.Since,
+			StartedAt: optionalTime(status.StartedAt),
+			LastError: errorString(status.Err),
+			Retries:   status.Retries,
+		}
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (server *Server) handleHealth(ok func(services.HealthReport) bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		report := server.runner.Health(r.Context())
+		result := HealthReport{
+			Live:      report.Live,
+			Ready:     report.Ready,
+			CheckedAt: report.CheckedAt,
+			Services:  make([]ServiceHealth, len(report.Services)),
+		}
+		for idx, health := range report.Services {
+			result.Services[idx] = ServiceHealth{
+				Name:          health.Name,
+				Status:        string(health.Status),
+				LastError:     errorString(health.LastError),
+				Latency:       health.Latency.String(),
+				CheckedAt:     health.CheckedAt,
+				LastHealthyAt: optionalTime(health.LastHealthyAt),
+			}
+		}
+
+		status := http.StatusOK
+		if !ok(report) {
+			status = http.StatusServiceUnavailable
+		}
+		writeJSON(w, status, result)
+	}
+}
+
+func (server *Server) handleShutdown(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if server.token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(server.token)) != 1 {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]string{"status": "shutting down"})
+	// The shutdown closes this server, which waits this request to finish. So, it cannot block the request.
+	go server.shutdown()
+}
+
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,13 @@
+package adminserver_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestAdminServer(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Admin Server Tests")
+}
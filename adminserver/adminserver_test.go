@@ -0,0 +1,209 @@
+package adminserver_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/setare/go-services"
+	"github.com/setare/go-services/adminserver"
+	"github.com/setare/go-services/servicestest"
+)
+
+type resource struct {
+	name      string
+	startErrs []error
+	checkErr  error
+}
+
+func (r *resource) Name() string { return r.name }
+
+func (r *resource) Start(context.Context) error {
+	if len(r.startErrs) > 0 {
+		err := r.startErrs[0]
+		r.startErrs = r.startErrs[1:]
+		return err
+	}
+	return nil
+}
+
+func (r *resource) Stop(context.Context) error { return nil }
+
+func (r *resource) Check(context.Context) error { return r.checkErr }
+
+func request(method, url, token string) (*http.Response, map[string]interface{}) {
+	req, err := http.NewRequest(method, url, nil)
+	Expect(err).ToNot(HaveOccurred())
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
+	Expect(resp.Header.Get("Content-Type")).To(Equal("application/json"))
+
+	var body interface{}
+	Expect(json.NewDecoder(resp.Body).Decode(&body)).To(Succeed())
+	if m, ok := body.(map[string]interface{}); ok {
+		return resp, m
+	}
+	return resp, map[string]interface{}{"items": body}
+}
+
+var _ = Describe("Server", func() {
+	var _ services.Server = &adminserver.Server{}
+
+	It("should list the services", func() {
+		ctx := context.TODO()
+
+		serviceA := &resource{name: "Service A"}
+		serviceB := services.Retrier().
+			Backoff(backoff.NewConstantBackOff(time.Millisecond)).
+			Build(&resource{name: "Service B", startErrs: []error{errors.New("random error")}})
+		serviceC := &resource{name: "Service C", startErrs: []error{errors.New("connection refused")}}
+
+		runner := services.NewRunner()
+		Expect(runner.Run(ctx, serviceA, serviceB, serviceC)).To(MatchError("connection refused"))
+
+		srv := httptest.NewServer(adminserver.New("Admin", runner).Handler())
+		defer srv.Close()
+
+		resp, body := request(http.MethodGet, srv.URL+"/services", "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		items := body["items"].([]interface{})
+		Expect(items).To(HaveLen(3))
+		Expect(items[0]).To(HaveKeyWithValue("name", "Service A"))
+		Expect(items[0]).To(HaveKeyWithValue("state", "running"))
+		Expect(items[0]).To(HaveKeyWithValue("retries", float64(0)))
+		Expect(items[0]).To(HaveKey("since"))
+		Expect(items[0]).To(HaveKey("started_at"))
+		Expect(items[0]).ToNot(HaveKey("last_error"))
+		Expect(items[1]).To(HaveKeyWithValue("name", "Service B"))
+		Expect(items[1]).To(HaveKeyWithValue("retries", float64(1)))
+		Expect(items[2]).To(HaveKeyWithValue("name", "Service C"))
+		Expect(items[2]).To(HaveKeyWithValue("state", "failed"))
+		Expect(items[2]).To(HaveKeyWithValue("last_error", "connection refused"))
+		Expect(items[2]).ToNot(HaveKey("started_at"))
+	})
+
+	It("should report the health and readiness", func() {
+		ctx := context.TODO()
+
+		serviceA := &resource{name: "Service A"}
+
+		runner := services.NewRunner()
+		srv := httptest.NewServer(adminserver.New("Admin", runner).Handler())
+		defer srv.Close()
+
+		resp, body := request(http.MethodGet, srv.URL+"/readyz", "")
+		Expect(resp.StatusCode).To(Equal(http.StatusServiceUnavailable))
+		Expect(body).To(HaveKeyWithValue("ready", false))
+
+		Expect(runner.Run(ctx, serviceA)).To(Succeed())
+
+		resp, body = request(http.MethodGet, srv.URL+"/readyz", "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(body).To(HaveKeyWithValue("ready", true))
+
+		serviceA.checkErr = errors.New("connection lost")
+		resp, body = request(http.MethodGet, srv.URL+"/healthz", "")
+		Expect(resp.StatusCode).To(Equal(http.StatusServiceUnavailable))
+		Expect(body).To(HaveKeyWithValue("live", false))
+		Expect(body["services"]).To(ConsistOf(SatisfyAll(
+			HaveKeyWithValue("name", "Service A"),
+			HaveKeyWithValue("status", "unhealthy"),
+			HaveKeyWithValue("last_error", "connection lost"),
+		)))
+
+		resp, _ = request(http.MethodPost, srv.URL+"/healthz", "")
+		Expect(resp.StatusCode).To(Equal(http.StatusMethodNotAllowed))
+	})
+
+	It("should not expose the shutdown unless it is enabled", func() {
+		srv := httptest.NewServer(adminserver.New("Admin", services.NewRunner()).Handler())
+		defer srv.Close()
+
+		resp, err := http.Post(srv.URL+"/shutdown", "", nil)
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+	})
+
+	It("should trigger the shutdown when authenticated", func() {
+		var shutdowns int32
+		admin := adminserver.New("Admin", services.NewRunner(), adminserver.WithShutdown("secret", func() {
+			atomic.AddInt32(&shutdowns, 1)
+		}))
+		srv := httptest.NewServer(admin.Handler())
+		defer srv.Close()
+
+		resp, _ := request(http.MethodPost, srv.URL+"/shutdown", "")
+		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+		resp, _ = request(http.MethodPost, srv.URL+"/shutdown", "wrong")
+		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+		resp, _ = request(http.MethodGet, srv.URL+"/shutdown", "secret")
+		Expect(resp.StatusCode).To(Equal(http.StatusMethodNotAllowed))
+		Consistently(func() int32 { return atomic.LoadInt32(&shutdowns) }, time.Millisecond*50).Should(BeZero())
+
+		resp, _ = request(http.MethodPost, srv.URL+"/shutdown", "secret")
+		Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
+		Eventually(func() int32 { return atomic.LoadInt32(&shutdowns) }).Should(Equal(int32(1)))
+	})
+
+	It("should run alongside other servers", func() {
+		ctx, cancelFunc := context.WithCancel(context.TODO())
+		defer cancelFunc()
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).ToNot(HaveOccurred())
+
+		runner := services.NewRunner()
+		admin := adminserver.New("Admin", runner,
+			adminserver.WithListener(listener),
+			adminserver.WithShutdown("secret", cancelFunc),
+		)
+
+		runErr := make(chan error, 1)
+		go func() {
+			runErr <- runner.Run(ctx, &resource{name: "Service A"}, admin)
+		}()
+
+		url := "http://" + listener.Addr().String()
+		Eventually(func() int {
+			resp, err := http.Get(url + "/readyz")
+			if err != nil {
+				return 0
+			}
+			resp.Body.Close()
+			return resp.StatusCode
+		}).Should(Equal(http.StatusOK))
+
+		resp, body := request(http.MethodGet, url+"/services", "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(body["items"]).To(ConsistOf(
+			HaveKeyWithValue("name", "Service A"),
+			HaveKeyWithValue("name", "Admin"),
+		))
+
+		resp, _ = request(http.MethodPost, url+"/shutdown", "secret")
+		Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
+		Eventually(runErr).Should(Receive(MatchError(context.Canceled)))
+	})
+})
+
+func TestVerifyServer(t *testing.T) {
+	servicestest.VerifyServer(t, func() services.Server {
+		return adminserver.New("Admin", services.NewRunner(), adminserver.WithAddr("127.0.0.1:0"))
+	})
+}
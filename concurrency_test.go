@@ -0,0 +1,258 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/setare/go-services"
+)
+
+// stopLog records the order the resources were stopped.
+type stopLog struct {
+	mutex   sync.Mutex
+	stopped []string
+}
+
+func (l *stopLog) add(name string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.stopped = append(l.stopped, name)
+}
+
+func (l *stopLog) names() []string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	result := make([]string, len(l.stopped))
+	copy(result, l.stopped)
+	return result
+}
+
+// countingResource is a thread-safe Resource that counts how many times it was started and stopped.
+type countingResource struct {
+	name string
+	log  *stopLog
+
+	mutex   sync.Mutex
+	starts  int
+	stops   int
+	running bool
+	invalid bool
+}
+
+func newCountingResource(name string, log *stopLog) *countingResource {
+	return &countingResource{
+		name: name,
+		log:  log,
+	}
+}
+
+func (r *countingResource) Name() string {
+	return r.name
+}
+
+func (r *countingResource) Start(context.Context) error {
+	time.Sleep(time.Millisecond)
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.running {
+		r.invalid = true
+	}
+	r.starts++
+	r.running = true
+	return nil
+}
+
+func (r *countingResource) Stop(context.Context) error {
+	time.Sleep(time.Millisecond)
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if !r.running {
+		r.invalid = true
+	}
+	r.stops++
+	r.running = false
+	if r.log != nil {
+		r.log.add(r.name)
+	}
+	return nil
+}
+
+// counts returns how many times the resource was started and stopped, and whether it was ever started twice in a row
+// or stopped without being started.
+func (r *countingResource) counts() (int, int, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.starts, r.stops, r.invalid
+}
+
+var _ = Describe("Concurrency", func() {
+	It("should start resources from many goroutines", func() {
+		ctx := context.TODO()
+
+		const (
+			goroutines = 20
+			perRun     = 5
+		)
+
+		log := &stopLog{}
+		runner := services.NewRunner()
+
+		resources := make([][]*countingResource, goroutines)
+		var wg sync.WaitGroup
+		wg.Add(goroutines)
+		for g := 0; g < goroutines; g++ {
+			resources[g] = make([]*countingResource, perRun)
+			ss := make([]services.Service, perRun)
+			for i := 0; i < perRun; i++ {
+				resources[g][i] = newCountingResource(fmt.Sprintf("Resource %d.%d", g, i), log)
+				ss[i] = resources[g][i]
+			}
+			go func(ss []services.Service) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				Expect(runner.Run(ctx, ss...)).To(Succeed())
+			}(ss)
+		}
+		wg.Wait()
+
+		Expect(runner.Services()).To(HaveLen(goroutines * perRun))
+		for _, status := range runner.Services() {
+			Expect(status.State).To(Equal(services.StateRunning))
+		}
+		Expect(runner.Health(ctx).Services).To(HaveLen(goroutines * perRun))
+
+		Expect(runner.Finish(ctx)).To(Succeed())
+
+		// Each Run must have its resources stopped in the reverse order they were started.
+		stopped := log.names()
+		Expect(stopped).To(HaveLen(goroutines * perRun))
+		for g := 0; g < goroutines; g++ {
+			positions := make([]int, perRun)
+			for i, resource := range resources[g] {
+				starts, stops, invalid := resource.counts()
+				Expect(starts).To(Equal(1))
+				Expect(stops).To(Equal(1))
+				Expect(invalid).To(BeFalse())
+
+				for pos, name := range stopped {
+					if name == resource.name {
+						positions[i] = pos
+					}
+				}
+			}
+			for i := 1; i < perRun; i++ {
+				Expect(positions[i]).To(BeNumerically("<", positions[i-1]))
+			}
+		}
+	})
+
+	It("should not stop a resource twice when Finish is called concurrently", func() {
+		ctx := context.TODO()
+
+		runner := services.NewRunner()
+		resources := make([]*countingResource, 50)
+		ss := make([]services.Service, len(resources))
+		for i := range resources {
+			resources[i] = newCountingResource(fmt.Sprintf("Resource %d", i), nil)
+			ss[i] = resources[i]
+		}
+		Expect(runner.Run(ctx, ss...)).To(Succeed())
+
+		var wg sync.WaitGroup
+		wg.Add(10)
+		for i := 0; i < 10; i++ {
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				Expect(runner.Finish(ctx)).To(Succeed())
+			}()
+		}
+		wg.Wait()
+
+		for _, resource := range resources {
+			starts, stops, invalid := resource.counts()
+			Expect(starts).To(Equal(1))
+			Expect(stops).To(Equal(1))
+			Expect(invalid).To(BeFalse())
+		}
+	})
+
+	It("should interleave Run and Finish calls", func() {
+		ctx := context.TODO()
+
+		runner := services.NewRunner()
+		resources := make([]*countingResource, 10)
+		for i := range resources {
+			resources[i] = newCountingResource(fmt.Sprintf("Resource %d", i), nil)
+		}
+
+		var wg sync.WaitGroup
+		wg.Add(len(resources) + 1)
+		for _, resource := range resources {
+			go func(resource *countingResource) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				for i := 0; i < 10; i++ {
+					err := runner.Run(ctx, resource)
+					// A concurrent Finish might not have stopped the resource yet.
+					if err != nil {
+						Expect(err).To(MatchError(services.ErrInvalidStateTransition))
+					}
+				}
+			}(resource)
+		}
+		go func() {
+			defer GinkgoRecover()
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				Expect(runner.Finish(ctx)).To(Succeed())
+			}
+		}()
+		wg.Wait()
+		Expect(runner.Finish(ctx)).To(Succeed())
+
+		for _, resource := range resources {
+			starts, stops, invalid := resource.counts()
+			Expect(starts).To(BeNumerically(">=", 1))
+			Expect(stops).To(Equal(starts))
+			Expect(invalid).To(BeFalse())
+		}
+	})
+
+	It("should start a resource given to concurrent Run calls only once", func() {
+		ctx := context.TODO()
+
+		runner := services.NewRunner()
+		resource := newCountingResource("Resource", nil)
+
+		errs := make(chan error, 10)
+		var wg sync.WaitGroup
+		wg.Add(cap(errs))
+		for i := 0; i < cap(errs); i++ {
+			go func() {
+				defer wg.Done()
+				errs <- runner.Run(ctx, resource)
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		succeeded := 0
+		for err := range errs {
+			if err == nil {
+				succeeded++
+				continue
+			}
+			Expect(err).To(MatchError(services.ErrInvalidStateTransition))
+		}
+		Expect(succeeded).To(Equal(1))
+
+		starts, _, _ := resource.counts()
+		Expect(starts).To(Equal(1))
+	})
+})
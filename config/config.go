@@ -0,0 +1,364 @@
+// Package config populates the configuration structs of services.Configurable services from defaults, YAML/JSON files
+// and environment variables, validating the result.
+//
+// The fields are configured by struct tags:
+//
+//   - `default:"value"`: the value used when no other source sets the field;
+//   - `yaml:"name"` and `json:"name"`: the key of the field in the YAML and JSON files;
+//   - `env:"NAME"`: the environment variable that sets the field (check WithEnvPrefix);
+//   - `validate:"required"`: the field must not be empty after loading all sources;
+//   - `secret:"true"`: the value is redacted by Redact.
+//
+// The sources are applied in that order, so environment variables override files, which override defaults. Nested
+// structs are supported. The values of the defaults and environment variables can be strings, booleans, numbers,
+// `time.Duration`, comma separated slices or any type implementing `encoding.TextUnmarshaler`.
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/setare/go-errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/setare/go-services"
+)
+
+const (
+	// ErrRequired is the error of a field tagged with `validate:"required"` that was not set.
+	ErrRequired = errors.Error("is required")
+
+	// ErrInvalidValue is the error of a field whose default, or environment variable, value cannot be parsed.
+	ErrInvalidValue = errors.Error("invalid value")
+
+	// ErrUnsupportedFile is returned when a file is not YAML (.yaml or .yml) nor JSON (.json).
+	ErrUnsupportedFile = errors.Error("unsupported file format")
+
+	// ErrInvalidConfig is matched by all the errors returned by Loader.Load.
+	ErrInvalidConfig = errors.Error("invalid configuration")
+)
+
+// Error is the error returned when the configuration of a service cannot be loaded. Field is the path of the field
+// (e.g. "Database.Host"), it is empty when the error is not related to a single field. It matches ErrInvalidConfig
+// when using `errors.Is`.
+type Error struct {
+	Service string
+	Field   string
+	Err     error
+}
+
+func (err *Error) Error() string {
+	if err.Field == "" {
+		return fmt.Sprintf("%s of %s: %s", ErrInvalidConfig, err.Service, err.Err)
+	}
+	return fmt.Sprintf("%s of %s: %s %s", ErrInvalidConfig, err.Service, err.Field, err.Err)
+}
+
+// Is implements the `errors.Is` support.
+func (err *Error) Is(target error) bool {
+	return target == ErrInvalidConfig
+}
+
+// Unwrap returns the cause of the error.
+func (err *Error) Unwrap() error {
+	return err.Err
+}
+
+// Validator describes a configuration struct that validates itself after it is loaded. If Validate returns an *Error,
+// its Service is filled by Loader.Load.
+type Validator interface {
+	Validate() error
+}
+
+// Loader populates configuration structs. The same Loader can be shared by many services.
+type Loader struct {
+	files     []string
+	envPrefix string
+	lookupEnv func(string) (string, bool)
+}
+
+// Option configures a Loader created by New.
+type Option = func(*Loader)
+
+// WithFiles is an Option that adds YAML (.yaml or .yml) or JSON (.json) files to be loaded, in the given order. All
+// files must exist.
+func WithFiles(paths ...string) Option {
+	return func(loader *Loader) {
+		loader.files = append(loader.files, paths...)
+	}
+}
+
+// WithEnvPrefix is an Option that sets the prefix added to the names given by the `env` tags. For example, with the
+// prefix "APP_", the tag `env:"DB_HOST"` reads the variable "APP_DB_HOST".
+func WithEnvPrefix(prefix string) Option {
+	return func(loader *Loader) {
+		loader.envPrefix = prefix
+	}
+}
+
+// WithLookupEnv is an Option that sets the function used to read the environment variables. The default is
+// `os.LookupEnv`.
+func WithLookupEnv(lookupEnv func(string) (string, bool)) Option {
+	return func(loader *Loader) {
+		loader.lookupEnv = lookupEnv
+	}
+}
+
+// New creates a new Loader.
+func New(opts ...Option) *Loader {
+	loader := &Loader{
+		lookupEnv: os.LookupEnv,
+	}
+	for _, opt := range opts {
+		opt(loader)
+	}
+	return loader
+}
+
+// Load populates the struct pointed by target with the configuration of the given service. It is meant to be called
+// by the services.Configurable.Load:
+//
+//	func (db *Database) Load(ctx context.Context) error {
+//		return db.loader.Load(ctx, db, &db.config)
+//	}
+//
+// All fields are checked, the errors are returned together in a services.MultiErrors (or the error itself, when there
+// is only one). Each of them is an *Error naming the service and the field.
+func (loader *Loader) Load(_ context.Context, service services.Service, target interface{}) error {
+	name := service.Name()
+
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return &Error{
+			Service: name,
+			Err:     fmt.Errorf("the target must be a pointer to a struct, got %T", target),
+		}
+	}
+	value = value.Elem()
+
+	errs := make(services.MultiErrors, 0)
+	fieldErr := func(path string, err error) {
+		errs = append(errs, &Error{
+			Service: name,
+			Field:   path,
+			Err:     err,
+		})
+	}
+
+	walk(value, "", func(field reflect.StructField, value reflect.Value, path string) {
+		raw, ok := field.Tag.Lookup("default")
+		if !ok || !value.IsZero() {
+			return
+		}
+		if err := setValue(value, raw); err != nil {
+			fieldErr(path, fmt.Errorf("%w: default %q: %v", ErrInvalidValue, raw, err))
+		}
+	})
+
+	for _, file := range loader.files {
+		if err := loadFile(file, target); err != nil {
+			errs = append(errs, &Error{
+				Service: name,
+				Err:     err,
+			})
+		}
+	}
+
+	walk(value, "", func(field reflect.StructField, value reflect.Value, path string) {
+		env, ok := field.Tag.Lookup("env")
+		if !ok || env == "" {
+			return
+		}
+		env = loader.envPrefix + env
+		raw, ok := loader.lookupEnv(env)
+		if !ok {
+			return
+		}
+		if err := setValue(value, raw); err != nil {
+			// The value of a secret is never shown, not even by the parsing error.
+			if isSecret(field) {
+				fieldErr(path, fmt.Errorf("%w: %s: cannot be parsed as %s", ErrInvalidValue, env, value.Type()))
+				return
+			}
+			fieldErr(path, fmt.Errorf("%w: %s=%q: %v", ErrInvalidValue, env, raw, err))
+		}
+	})
+
+	walk(value, "", func(field reflect.StructField, value reflect.Value, path string) {
+		if hasRule(field, "required") && value.IsZero() {
+			fieldErr(path, ErrRequired)
+		}
+	})
+
+	if len(errs) == 0 {
+		if validator, ok := target.(Validator); ok {
+			if err := validator.Validate(); err != nil {
+				if configErr, ok := err.(*Error); ok {
+					configErr.Service = name
+					errs = append(errs, configErr)
+				} else {
+					fieldErr("", err)
+				}
+			}
+		}
+	}
+
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return errs
+	}
+}
+
+// loadFile decodes the given file into target, accordingly to its extension.
+func loadFile(path string, target interface{}) error {
+	unmarshal := yaml.Unmarshal
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+	case ".json":
+		unmarshal = json.Unmarshal
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedFile, path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := unmarshal(data, target); err != nil {
+		return fmt.Errorf("failed decoding %s: %w", path, err)
+	}
+	return nil
+}
+
+// Redact returns the given configuration struct (or pointer to it) as a map, replacing the values of the fields tagged
+// with `secret:"true"` by "[REDACTED]". Secrets that are not set are kept empty, so it is possible to tell whether they
+// were set. The keys are the same used by the YAML files. It is meant to implement services.ConfigDumper:
+//
+//	func (db *Database) DumpConfig() interface{} {
+//		return config.Redact(db.config)
+//	}
+func Redact(config interface{}) map[string]interface{} {
+	value := reflect.Indirect(reflect.ValueOf(config))
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+	return redactStruct(value)
+}
+
+// Redacted is the value that replaces the secrets in Redact.
+const Redacted = "[REDACTED]"
+
+func redactStruct(value reflect.Value) map[string]interface{} {
+	result := make(map[string]interface{}, value.NumField())
+	for idx := 0; idx < value.NumField(); idx++ {
+		field := value.Type().Field(idx)
+		if field.PkgPath != "" {
+			continue
+		}
+		key := keyOf(field)
+		if key == "-" {
+			continue
+		}
+		fieldValue := value.Field(idx)
+		switch {
+		case isSecret(field):
+			if fieldValue.IsZero() {
+				result[key] = ""
+			} else {
+				result[key] = Redacted
+			}
+		default:
+			result[key] = redactValue(fieldValue)
+		}
+	}
+	return result
+}
+
+// redactValue returns the value to be dumped. The structs are redacted, even when they are held by pointers,
+// interfaces, slices, arrays or maps.
+func redactValue(value reflect.Value) interface{} {
+	if value.Kind() == reflect.Struct {
+		if isNested(value) {
+			return redactStruct(value)
+		}
+		return plainValue(value)
+	}
+	if !holdsNested(value.Type()) {
+		return plainValue(value)
+	}
+
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+		return redactValue(value.Elem())
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return nil
+		}
+		result := make([]interface{}, value.Len())
+		for idx := range result {
+			result[idx] = redactValue(value.Index(idx))
+		}
+		return result
+	case reflect.Map:
+		if value.IsNil() {
+			return nil
+		}
+		result := make(map[string]interface{}, value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			result[fmt.Sprint(iter.Key().Interface())] = redactValue(iter.Value())
+		}
+		return result
+	}
+	return plainValue(value)
+}
+
+// holdsNested checks if the values of the given type can hold nested structs (check isNested), which might have
+// secrets. The interfaces are checked by redactValue with the value they hold.
+func holdsNested(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Struct:
+		return !reflect.PointerTo(t).Implements(textUnmarshalerType)
+	case reflect.Interface:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return holdsNested(t.Elem())
+	}
+	return false
+}
+
+// keyOf returns the key of the field in the YAML files: the name from the yaml tag, or the json tag, or the lowercased
+// field name.
+func keyOf(field reflect.StructField) string {
+	for _, tag := range []string{"yaml", "json"} {
+		if name := strings.Split(field.Tag.Get(tag), ",")[0]; name != "" {
+			return name
+		}
+	}
+	return strings.ToLower(field.Name)
+}
+
+func isSecret(field reflect.StructField) bool {
+	return field.Tag.Get("secret") == "true"
+}
+
+func hasRule(field reflect.StructField, rule string) bool {
+	for _, r := range strings.Split(field.Tag.Get("validate"), ",") {
+		if strings.TrimSpace(r) == rule {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,306 @@
+package config_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/setare/go-services"
+	"github.com/setare/go-services/config"
+)
+
+type databaseConfig struct {
+	Host     string        `yaml:"host" json:"host" env:"DB_HOST" default:"localhost"`
+	Port     int           `yaml:"port" json:"port" env:"DB_PORT" default:"5432"`
+	User     string        `yaml:"user" json:"user" env:"DB_USER" validate:"required"`
+	Password string        `yaml:"password" json:"password" env:"DB_PASSWORD" secret:"true"`
+	Timeout  time.Duration `yaml:"timeout" json:"timeout" env:"DB_TIMEOUT" default:"5s"`
+}
+
+type appConfig struct {
+	Name     string         `yaml:"name" json:"name" env:"NAME" validate:"required"`
+	Debug    bool           `yaml:"debug" json:"debug" env:"DEBUG"`
+	Tags     []string       `yaml:"tags" json:"tags" env:"TAGS"`
+	Database databaseConfig `yaml:"database" json:"database"`
+}
+
+// database is a services.Configurable that loads its configuration using a config.Loader.
+type database struct {
+	loader *config.Loader
+	config appConfig
+}
+
+func (db *database) Name() string                   { return "Database" }
+func (db *database) Start(context.Context) error    { return nil }
+func (db *database) Stop(context.Context) error     { return nil }
+func (db *database) Load(ctx context.Context) error { return db.loader.Load(ctx, db, &db.config) }
+func (db *database) DumpConfig() interface{}        { return config.Redact(db.config) }
+
+type validatedConfig struct {
+	Min int `env:"MIN"`
+	Max int `env:"MAX"`
+}
+
+func (cfg *validatedConfig) Validate() error {
+	if cfg.Min > cfg.Max {
+		return &config.Error{Field: "Min", Err: errors.New("must not be greater than Max")}
+	}
+	return nil
+}
+
+func lookupEnv(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+}
+
+var _ = Describe("Loader", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "config")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		Expect(os.WriteFile(path, []byte(content), 0o600)).To(Succeed())
+		return path
+	}
+
+	It("should load the defaults, the files and the environment variables in order", func() {
+		yamlFile := writeFile("config.yaml", `
+name: app
+tags: [a, b]
+database:
+  host: db.local
+  user: admin
+  password: from-yaml
+`)
+		jsonFile := writeFile("config.json", `{"database": {"port": 6432}}`)
+
+		db := &database{
+			loader: config.New(
+				config.WithFiles(yamlFile, jsonFile),
+				config.WithEnvPrefix("APP_"),
+				config.WithLookupEnv(lookupEnv(map[string]string{
+					"APP_DB_PASSWORD": "from-env",
+					"APP_DEBUG":       "true",
+					"APP_DB_TIMEOUT":  "1m",
+					"DB_USER":         "ignored",
+				})),
+			),
+		}
+		Expect(db.Load(context.TODO())).To(Succeed())
+		Expect(db.config).To(Equal(appConfig{
+			Name:  "app",
+			Debug: true,
+			Tags:  []string{"a", "b"},
+			Database: databaseConfig{
+				Host:     "db.local",
+				Port:     6432,
+				User:     "admin",
+				Password: "from-env",
+				Timeout:  time.Minute,
+			},
+		}))
+	})
+
+	It("should parse slices from environment variables", func() {
+		db := &database{
+			loader: config.New(config.WithLookupEnv(lookupEnv(map[string]string{
+				"NAME":    "app",
+				"TAGS":    "a, b,c",
+				"DB_USER": "admin",
+			}))),
+		}
+		Expect(db.Load(context.TODO())).To(Succeed())
+		Expect(db.config.Tags).To(Equal([]string{"a", "b", "c"}))
+	})
+
+	It("should name the service and the fields that are invalid", func() {
+		db := &database{
+			loader: config.New(config.WithLookupEnv(lookupEnv(map[string]string{
+				"DB_PORT":     "not a number",
+				"DB_PASSWORD": "secret",
+				"DB_TIMEOUT":  "secret",
+			}))),
+		}
+		db.config.Database.Password = "placeholder"
+
+		err := db.Load(context.TODO())
+		Expect(err).To(MatchError(config.ErrInvalidConfig))
+		Expect(err).To(MatchError(config.ErrRequired))
+		Expect(err).To(MatchError(config.ErrInvalidValue))
+
+		var errs services.MultiErrors
+		Expect(errors.As(err, &errs)).To(BeTrue())
+		messages := make([]string, len(errs))
+		for idx, err := range errs {
+			messages[idx] = err.Error()
+		}
+		Expect(messages).To(Equal([]string{
+			`invalid configuration of Database: Database.Port invalid value: DB_PORT="not a number": strconv.ParseInt: parsing "not a number": invalid syntax`,
+			`invalid configuration of Database: Database.Timeout invalid value: DB_TIMEOUT="secret": time: invalid duration "secret"`,
+			`invalid configuration of Database: Name is required`,
+			`invalid configuration of Database: Database.User is required`,
+		}))
+
+		var configErr *config.Error
+		Expect(errors.As(err, &configErr)).To(BeTrue())
+		Expect(configErr.Service).To(Equal("Database"))
+		Expect(configErr.Field).To(Equal("Database.Port"))
+	})
+
+	It("should not show the value of invalid secrets", func() {
+		type secretConfig struct {
+			Key int `env:"KEY" secret:"true"`
+		}
+
+		var cfg secretConfig
+		loader := config.New(config.WithLookupEnv(lookupEnv(map[string]string{"KEY": "super-secret"})))
+		err := loader.Load(context.TODO(), &database{}, &cfg)
+		Expect(err).To(MatchError(config.ErrInvalidValue))
+		Expect(err.Error()).ToNot(ContainSubstring("super-secret"))
+	})
+
+	It("should validate the configuration", func() {
+		var cfg validatedConfig
+		loader := config.New(config.WithLookupEnv(lookupEnv(map[string]string{"MIN": "2", "MAX": "1"})))
+		err := loader.Load(context.TODO(), &database{}, &cfg)
+		Expect(err).To(MatchError(config.ErrInvalidConfig))
+		Expect(err.Error()).To(Equal("invalid configuration of Database: Min must not be greater than Max"))
+	})
+
+	It("should fail loading files that do not exist or are not supported", func() {
+		var cfg appConfig
+		loader := config.New(config.WithFiles(filepath.Join(dir, "missing.yaml"), writeFile("config.toml", "")))
+		err := loader.Load(context.TODO(), &database{}, &cfg)
+		Expect(err).To(MatchError(os.ErrNotExist))
+		Expect(err).To(MatchError(config.ErrUnsupportedFile))
+	})
+
+	It("should fail when the target is not a pointer to a struct", func() {
+		var cfg appConfig
+		err := config.New().Load(context.TODO(), &database{}, cfg)
+		Expect(err).To(MatchError(config.ErrInvalidConfig))
+	})
+
+	Describe("Redact", func() {
+		It("should redact the secrets", func() {
+			cfg := appConfig{
+				Name: "app",
+				Database: databaseConfig{
+					Host:     "localhost",
+					Password: "secret",
+					Timeout:  time.Second,
+				},
+			}
+			Expect(config.Redact(&cfg)).To(Equal(map[string]interface{}{
+				"name":  "app",
+				"debug": false,
+				"tags":  []string(nil),
+				"database": map[string]interface{}{
+					"host":     "localhost",
+					"port":     0,
+					"user":     "",
+					"password": config.Redacted,
+					"timeout":  "1s",
+				},
+			}))
+		})
+
+		type credentials struct {
+			User     string `yaml:"user"`
+			Password string `yaml:"password" secret:"true"`
+		}
+
+		redactedCredentials := map[string]interface{}{
+			"user":     "admin",
+			"password": config.Redacted,
+		}
+
+		It("should redact the secrets of structs held by pointers", func() {
+			type cfg struct {
+				DB      *credentials `yaml:"db"`
+				Replica *credentials `yaml:"replica"`
+			}
+			Expect(config.Redact(cfg{DB: &credentials{User: "admin", Password: "hunter2"}})).To(Equal(map[string]interface{}{
+				"db":      redactedCredentials,
+				"replica": nil,
+			}))
+		})
+
+		It("should redact the secrets of structs held by slices and arrays", func() {
+			type cfg struct {
+				Shards  []credentials   `yaml:"shards"`
+				Mirrors [1]*credentials `yaml:"mirrors"`
+				Extra   []interface{}   `yaml:"extra"`
+				Ports   []int           `yaml:"ports"`
+			}
+			Expect(config.Redact(cfg{
+				Shards:  []credentials{{User: "admin", Password: "hunter2"}},
+				Mirrors: [1]*credentials{{User: "admin", Password: "hunter2"}},
+				Extra:   []interface{}{credentials{User: "admin", Password: "hunter2"}, 1},
+				Ports:   []int{80},
+			})).To(Equal(map[string]interface{}{
+				"shards":  []interface{}{redactedCredentials},
+				"mirrors": []interface{}{redactedCredentials},
+				"extra":   []interface{}{redactedCredentials, 1},
+				"ports":   []int{80},
+			}))
+		})
+
+		It("should redact the secrets of structs held by maps", func() {
+			type cfg struct {
+				Tenants map[string]*credentials `yaml:"tenants"`
+				Regions map[int]credentials     `yaml:"regions"`
+			}
+			Expect(config.Redact(cfg{
+				Tenants: map[string]*credentials{"acme": {User: "admin", Password: "hunter2"}},
+				Regions: map[int]credentials{1: {User: "admin", Password: "hunter2"}},
+			})).To(Equal(map[string]interface{}{
+				"tenants": map[string]interface{}{"acme": redactedCredentials},
+				"regions": map[string]interface{}{"1": redactedCredentials},
+			}))
+		})
+	})
+
+	It("should dump the configuration when the Runner loads it", func() {
+		db := &database{
+			loader: config.New(config.WithLookupEnv(lookupEnv(map[string]string{
+				"NAME":        "app",
+				"DB_USER":     "admin",
+				"DB_PASSWORD": "secret",
+			}))),
+		}
+
+		var buf bytes.Buffer
+		runner := services.NewRunner(services.WithConfigDump(&buf))
+		Expect(runner.Run(context.TODO(), db)).To(Succeed())
+		Expect(buf.String()).ToNot(ContainSubstring("secret"))
+
+		var entry struct {
+			Service string                 `json:"service"`
+			Config  map[string]interface{} `json:"config"`
+		}
+		Expect(json.Unmarshal(buf.Bytes(), &entry)).To(Succeed())
+		Expect(entry.Service).To(Equal("Database"))
+		Expect(entry.Config).To(HaveKeyWithValue("name", "app"))
+		Expect(entry.Config["database"]).To(HaveKeyWithValue("password", config.Redacted))
+		Expect(entry.Config["database"]).To(HaveKeyWithValue("user", "admin"))
+	})
+})
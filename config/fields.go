@@ -0,0 +1,116 @@
+package config
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// walk calls fn for each exported field of the given struct, going through the nested structs. The path is the dotted
+// path of the field from the root struct (e.g. "Database.Host").
+func walk(value reflect.Value, prefix string, fn func(field reflect.StructField, value reflect.Value, path string)) {
+	for idx := 0; idx < value.NumField(); idx++ {
+		field := value.Type().Field(idx)
+		if field.PkgPath != "" {
+			continue
+		}
+		path := field.Name
+		if prefix != "" {
+			path = prefix + "." + field.Name
+		}
+		fieldValue := value.Field(idx)
+		if isNested(fieldValue) {
+			walk(fieldValue, path, fn)
+			continue
+		}
+		fn(field, fieldValue, path)
+	}
+}
+
+// isNested checks if the value is a struct whose fields are configured one by one. Structs that can be parsed from a
+// text (like `time.Time`) are not nested.
+func isNested(value reflect.Value) bool {
+	return value.Kind() == reflect.Struct && !reflect.PointerTo(value.Type()).Implements(textUnmarshalerType)
+}
+
+// setValue parses raw into the given value.
+func setValue(value reflect.Value, raw string) error {
+	if value.CanAddr() && value.Addr().Type().Implements(textUnmarshalerType) {
+		return value.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw))
+	}
+
+	if value.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		value.SetInt(int64(d))
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(raw, 0, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(raw, 0, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetFloat(f)
+	case reflect.Slice:
+		parts := make([]string, 0)
+		if raw != "" {
+			parts = strings.Split(raw, ",")
+		}
+		slice := reflect.MakeSlice(value.Type(), len(parts), len(parts))
+		for idx, part := range parts {
+			if err := setValue(slice.Index(idx), strings.TrimSpace(part)); err != nil {
+				return err
+			}
+		}
+		value.Set(slice)
+	case reflect.Ptr:
+		elem := reflect.New(value.Type().Elem())
+		if err := setValue(elem.Elem(), raw); err != nil {
+			return err
+		}
+		value.Set(elem)
+	default:
+		return fmt.Errorf("unsupported type %s", value.Type())
+	}
+	return nil
+}
+
+// plainValue returns the value to be dumped. Durations are shown as text.
+func plainValue(value reflect.Value) interface{} {
+	if value.Type() == durationType {
+		return time.Duration(value.Int()).String()
+	}
+	return value.Interface()
+}
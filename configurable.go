@@ -2,6 +2,8 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"io"
 )
 
 // Configurable describes a service that should be loaded before started.
@@ -13,3 +15,47 @@ type Configurable interface {
 	// Load will load the configuration
 	Load(ctx context.Context) error
 }
+
+// ConfigDumper describes a Configurable service that exposes its effective configuration, so it can be dumped when it
+// is loaded (check WithConfigDump). The secrets must be redacted (check the `config` package).
+type ConfigDumper interface {
+	// DumpConfig returns the configuration of the service. It must be encodable as JSON.
+	DumpConfig() interface{}
+}
+
+// configDumpEntry is the line written by WithConfigDump for each service.
+type configDumpEntry struct {
+	Service string      `json:"service"`
+	Config  interface{} `json:"config"`
+}
+
+// WithConfigDump is a StarterOption that writes the configuration of each service that implements ConfigDumper to w,
+// right after it is loaded by Runner.Run. Each service is written as a JSON line with its name and configuration:
+//
+//	{"service":"Database","config":{"host":"localhost","password":"[REDACTED]"}}
+func WithConfigDump(w io.Writer) StarterOption {
+	return func(manager *Runner) {
+		manager.configDump = w
+	}
+}
+
+// dumpConfig writes the configuration of the given service, if enabled. Errors writing are ignored, since the dump is
+// only informative.
+func (r *Runner) dumpConfig(service Service) {
+	dumper, ok := As[ConfigDumper](service)
+	if r.configDump == nil || !ok {
+		return
+	}
+	line, err := json.Marshal(configDumpEntry{
+		Service: service.Name(),
+		Config:  dumper.DumpConfig(),
+	})
+	if err != nil {
+		return
+	}
+
+	// Services of the same dependency level are loaded in parallel.
+	r.configDumpMutex.Lock()
+	defer r.configDumpMutex.Unlock()
+	_, _ = r.configDump.Write(append(line, '\n'))
+}
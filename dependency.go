@@ -0,0 +1,163 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Dependent describes a service that depends on other services to be started before it.
+//
+// This interface is used by `Runner.Run`. When at least one of the given services declares dependencies, the Runner
+// builds a dependency graph and starts the services level by level: all services of the same level are started in
+// parallel, and a level only starts after the previous one has finished. The services returned by `DependsOn` must be
+// the same instances passed to `Runner.Run` (or to a previous `Runner.Run` call).
+type Dependent interface {
+	// DependsOn returns the list of services that must be started before this one.
+	DependsOn() []Service
+}
+
+// dependencyLevels groups the given services in levels that respect their declared dependencies. Each level only
+// depends on services of previous levels (or on the already started resources).
+//
+// If no service declares dependencies, each service is put in its own level, keeping the order they were given.
+func dependencyLevels(services []Service, started []Resource) ([][]Service, error) {
+	if !hasDependencies(services) {
+		levels := make([][]Service, len(services))
+		for idx, service := range services {
+			levels[idx] = []Service{service}
+		}
+		return levels, nil
+	}
+
+	indexes := make(map[Service]int, len(services))
+	for idx, service := range services {
+		indexes[service] = idx
+	}
+
+	isStarted := make(map[Service]bool, len(started))
+	for _, resource := range started {
+		isStarted[resource] = true
+	}
+
+	// pending holds the number of dependencies, of each service, that were not started yet. dependents holds the
+	// services that depend on each service.
+	pending := make([]int, len(services))
+	dependents := make([][]int, len(services))
+	for idx, service := range services {
+		dependent, ok := service.(Dependent)
+		if !ok {
+			continue
+		}
+		seen := make(map[int]bool)
+		for _, dependency := range dependent.DependsOn() {
+			if err := checkComparable(dependency); err != nil {
+				return nil, err
+			}
+			depIdx, ok := indexes[dependency]
+			if !ok {
+				if isStarted[dependency] {
+					continue
+				}
+				return nil, fmt.Errorf("%w: %s depends on %s", ErrDependencyNotFound, serviceName(service), serviceName(dependency))
+			}
+			if seen[depIdx] {
+				continue
+			}
+			seen[depIdx] = true
+			pending[idx]++
+			dependents[depIdx] = append(dependents[depIdx], idx)
+		}
+	}
+
+	levels := make([][]Service, 0)
+	current := make([]int, 0, len(services))
+	for idx := range services {
+		if pending[idx] == 0 {
+			current = append(current, idx)
+		}
+	}
+	resolved := 0
+	for len(current) > 0 {
+		level := make([]Service, len(current))
+		next := make([]int, 0)
+		for i, idx := range current {
+			level[i] = services[idx]
+			for _, dependentIdx := range dependents[idx] {
+				pending[dependentIdx]--
+				if pending[dependentIdx] == 0 {
+					next = append(next, dependentIdx)
+				}
+			}
+		}
+		resolved += len(current)
+		levels = append(levels, level)
+		sort.Ints(next)
+		current = next
+	}
+
+	if resolved < len(services) {
+		return nil, fmt.Errorf("%w: %s", ErrDependencyCycle, describeCycle(services, indexes, pending))
+	}
+	return levels, nil
+}
+
+// hasDependencies checks if any of the given services declare, at least, one dependency.
+func hasDependencies(services []Service) bool {
+	for _, service := range services {
+		if dependent, ok := service.(Dependent); ok && len(dependent.DependsOn()) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// describeCycle finds a cycle among the services that could not be resolved and returns it in a human readable form.
+// Ex: A -> B -> C -> A.
+func describeCycle(services []Service, indexes map[Service]int, pending []int) string {
+	// Every unresolved service has, at least, one unresolved dependency. So, following them will lead to a cycle.
+	next := func(idx int) int {
+		for _, dependency := range services[idx].(Dependent).DependsOn() {
+			if depIdx, ok := indexes[dependency]; ok && pending[depIdx] > 0 {
+				return depIdx
+			}
+		}
+		return -1
+	}
+
+	start := -1
+	for idx := range services {
+		if pending[idx] > 0 {
+			start = idx
+			break
+		}
+	}
+
+	visitedAt := make(map[int]int)
+	path := make([]int, 0)
+	for idx := start; idx >= 0; idx = next(idx) {
+		if at, ok := visitedAt[idx]; ok {
+			path = append(path[at:], idx)
+			break
+		}
+		visitedAt[idx] = len(path)
+		path = append(path, idx)
+	}
+
+	var r strings.Builder
+	for i, idx := range path {
+		if i > 0 {
+			r.WriteString(" -> ")
+		}
+		r.WriteString(serviceName(services[idx]))
+	}
+	return r.String()
+}
+
+// serviceName returns the name of the service handling nil values.
+func serviceName(service Service) string {
+	if service == nil {
+		return "<nil>"
+	}
+	return service.Name()
+}
@@ -0,0 +1,166 @@
+package services_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/setare/go-services"
+)
+
+type dependentResource struct {
+	*MockResource
+	*MockDependent
+}
+
+func newDependentResource(ctrl *gomock.Controller, name string) *dependentResource {
+	r := &dependentResource{
+		MockResource:  NewMockResource(ctrl),
+		MockDependent: NewMockDependent(ctrl),
+	}
+	r.MockResource.EXPECT().Name().Return(name).AnyTimes()
+	return r
+}
+
+func (r *dependentResource) dependsOn(services ...services.Service) {
+	r.MockDependent.EXPECT().DependsOn().Return(services).AnyTimes()
+}
+
+var _ = Describe("Dependencies", func() {
+	It("should start Resource instances respecting their dependencies", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		// 1. Create 3 resourceServices: C <- B <- A
+		serviceA := newDependentResource(ctrl, "Service A")
+		serviceB := newDependentResource(ctrl, "Service B")
+		serviceC := newDependentResource(ctrl, "Service C")
+		serviceA.dependsOn(serviceB)
+		serviceB.dependsOn(serviceC)
+		serviceC.dependsOn()
+
+		gomock.InOrder(
+			serviceC.MockResource.EXPECT().Start(gomock.Any()),
+			serviceB.MockResource.EXPECT().Start(gomock.Any()),
+			serviceA.MockResource.EXPECT().Start(gomock.Any()),
+			serviceA.MockResource.EXPECT().Stop(gomock.Any()),
+			serviceB.MockResource.EXPECT().Stop(gomock.Any()),
+			serviceC.MockResource.EXPECT().Stop(gomock.Any()),
+		)
+
+		// 2. Create and Run the Runner with the services in the "wrong" order.
+		runner := services.NewRunner()
+		Expect(runner.Run(ctx, serviceA, serviceB, serviceC)).To(Succeed())
+		Expect(runner.Finish(ctx)).To(Succeed())
+	})
+
+	It("should start independent Resource instances in parallel", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		// 1. Create 3 resourceServices: A and B are independent, C depends on both.
+		serviceA := newDependentResource(ctrl, "Service A")
+		serviceB := newDependentResource(ctrl, "Service B")
+		serviceC := newDependentResource(ctrl, "Service C")
+		serviceA.dependsOn()
+		serviceB.dependsOn()
+		serviceC.dependsOn(serviceA, serviceB)
+
+		var (
+			m       sync.Mutex
+			running int
+			maxRun  int
+		)
+		slowStart := func(context.Context) {
+			m.Lock()
+			running++
+			if running > maxRun {
+				maxRun = running
+			}
+			m.Unlock()
+			time.Sleep(time.Millisecond * 100)
+			m.Lock()
+			running--
+			m.Unlock()
+		}
+
+		startA := serviceA.MockResource.EXPECT().Start(gomock.Any()).Do(slowStart)
+		startB := serviceB.MockResource.EXPECT().Start(gomock.Any()).Do(slowStart)
+		serviceC.MockResource.EXPECT().Start(gomock.Any()).After(startA).After(startB)
+
+		// 2. Create and Run the Runner
+		runner := services.NewRunner()
+		startedAt := time.Now()
+		Expect(runner.Run(ctx, serviceC, serviceA, serviceB)).To(Succeed())
+		Expect(time.Since(startedAt)).To(BeNumerically("~", time.Millisecond*100, time.Millisecond*50))
+		Expect(maxRun).To(Equal(2))
+	})
+
+	It("should consider Resource instances started in previous calls", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		serviceA := newDependentResource(ctrl, "Service A")
+		serviceB := newDependentResource(ctrl, "Service B")
+		serviceA.dependsOn()
+		serviceB.dependsOn(serviceA)
+
+		gomock.InOrder(
+			serviceA.MockResource.EXPECT().Start(gomock.Any()),
+			serviceB.MockResource.EXPECT().Start(gomock.Any()),
+		)
+
+		runner := services.NewRunner()
+		Expect(runner.Run(ctx, serviceA)).To(Succeed())
+		Expect(runner.Run(ctx, serviceB)).To(Succeed())
+	})
+
+	It("should fail when a dependency is not found", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		serviceA := newDependentResource(ctrl, "Service A")
+		serviceB := newDependentResource(ctrl, "Service B")
+		serviceA.dependsOn(serviceB)
+
+		runner := services.NewRunner()
+		err := runner.Run(ctx, serviceA)
+		Expect(err).To(MatchError(services.ErrDependencyNotFound))
+		Expect(err.Error()).To(ContainSubstring("Service A depends on Service B"))
+	})
+
+	It("should fail when the dependencies form a cycle", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		// 1. Create 4 resourceServices: D -> A -> B -> C -> A
+		serviceA := newDependentResource(ctrl, "Service A")
+		serviceB := newDependentResource(ctrl, "Service B")
+		serviceC := newDependentResource(ctrl, "Service C")
+		serviceD := newDependentResource(ctrl, "Service D")
+		serviceA.dependsOn(serviceB)
+		serviceB.dependsOn(serviceC)
+		serviceC.dependsOn(serviceA)
+		serviceD.dependsOn(serviceA)
+
+		// 2. No service should be started.
+		runner := services.NewRunner()
+		err := runner.Run(ctx, serviceD, serviceA, serviceB, serviceC)
+		Expect(err).To(MatchError(services.ErrDependencyCycle))
+		Expect(err.Error()).To(ContainSubstring("Service A -> Service B -> Service C -> Service A"))
+	})
+})
@@ -6,4 +6,45 @@ const (
 	// ErrStartCancelledBySignal is returned when Runner.Run receives a shutdown signal while starting the list of
 	// Resource and Server.
 	ErrStartCancelledBySignal = errors.Error("start cancelled by signal")
+
+	// ErrStoppedBySignal is matched by the SignalError returned when Runner.Run is stopped by a signal.
+	ErrStoppedBySignal = errors.Error("stopped by signal")
+
+	// ErrDependencyCycle is returned when Runner.Run detects that the dependencies declared by the given services (check
+	// Dependent) form a cycle.
+	ErrDependencyCycle = errors.Error("dependency cycle detected")
+
+	// ErrDependencyNotFound is returned when Runner.Run detects that a service depends on another that was not given
+	// and was not started before.
+	ErrDependencyNotFound = errors.Error("dependency not found")
+
+	// ErrTooManyRestarts is returned by ServerSupervisor.Listen when the supervised servers are restarted more times
+	// than allowed.
+	ErrTooManyRestarts = errors.Error("too many restarts")
+
+	// ErrStopTimeout is matched by the StopTimeoutError recorded when a service does not stop within its shutdown
+	// timeout (check WithShutdownTimeout).
+	ErrStopTimeout = errors.Error("stop timeout")
+
+	// ErrInvalidStateTransition is matched by the StateTransitionError returned when a service cannot go from its
+	// current state to another. For example, when Runner.Run is called with a Resource that is already running.
+	ErrInvalidStateTransition = errors.Error("invalid state transition")
+
+	// ErrStartupTimeout is matched by the StartupTimeoutError returned when a Server does not become ready within the
+	// startup timeout (check WithStartupTimeout).
+	ErrStartupTimeout = errors.Error("startup timeout")
+
+	// ErrExitedBeforeReady is returned by Runner.Run when the Listen of a Server, that implements ReadyNotifier,
+	// returns before the server becomes ready.
+	ErrExitedBeforeReady = errors.Error("server exited before ready")
+
+	// ErrServiceNotComparable is returned when Runner.Run is given a service whose type is not comparable (e.g. a struct
+	// holding a slice), so it cannot be told apart from other services. Pass a pointer to it instead.
+	ErrServiceNotComparable = errors.Error("service is not comparable")
+
+	// ErrAlreadyListening is returned by Server.Listen when the server is already listening.
+	ErrAlreadyListening = errors.Error("already listening")
+
+	// ErrPanic is matched by the PanicError recorded when a service panics.
+	ErrPanic = errors.Error("panic recovered")
 )
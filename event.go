@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// EventKind identifies the reporter method that originated an Event.
+type EventKind string
+
+// The kinds of events, named after the methods of the reporter interfaces.
+const (
+	EventBeforeStart         EventKind = "BeforeStart"
+	EventAfterStart          EventKind = "AfterStart"
+	EventBeforeStop          EventKind = "BeforeStop"
+	EventAfterStop           EventKind = "AfterStop"
+	EventBeforeLoad          EventKind = "BeforeLoad"
+	EventAfterLoad           EventKind = "AfterLoad"
+	EventSignalReceived      EventKind = "SignalReceived"
+	EventBeforeRetry         EventKind = "BeforeRetry"
+	EventAfterRetry          EventKind = "AfterRetry"
+	EventBeforeRestart       EventKind = "BeforeRestart"
+	EventBeforeShutdownPhase EventKind = "BeforeShutdownPhase"
+	EventAfterShutdownPhase  EventKind = "AfterShutdownPhase"
+	EventPanicRecovered      EventKind = "PanicRecovered"
+	EventBeforeReload        EventKind = "BeforeReload"
+	EventAfterReload         EventKind = "AfterReload"
+	EventBeforeRun           EventKind = "BeforeRun"
+	EventAfterRun            EventKind = "AfterRun"
+	EventBeforeFinish        EventKind = "BeforeFinish"
+	EventAfterFinish         EventKind = "AfterFinish"
+)
+
+// Event is a call to one of the methods of the reporter interfaces. Only the fields related to its Kind are set.
+type Event struct {
+	Kind EventKind
+	// Time is when the event was reported.
+	Time    time.Time
+	Service Service
+	// Services are the services given to Runner.Run, set for EventBeforeRun.
+	Services []Service
+	Err      error
+	// Attempt is the attempt of a retry, or how many times the server was restarted.
+	Attempt int
+	// Delay is the delay before the next attempt of a retry (`backoff.Stop` when there is none).
+	Delay  time.Duration
+	Phase  string
+	Signal os.Signal
+	Panic  *PanicError
+
+	// configurable is the Configurable given to BeforeLoad and AfterLoad.
+	configurable Configurable
+}
+
+// EventReporter is a Reporter that turns the calls to all methods of the reporter interfaces (Reporter,
+// RetrierReporter, SupervisorReporter, PhaseReporter, PanicReporter, ReloadReporter and RunReporter) into Event
+// instances, so a reporter only has to handle one method:
+//
+//	reporter := services.NewEventReporter(func(ctx context.Context, event services.Event) {
+//		if event.Kind == services.EventAfterStart && event.Err != nil {
+//			alert(event.Service, event.Err)
+//		}
+//	})
+type EventReporter struct {
+	handle func(context.Context, Event) context.Context
+}
+
+// NewEventReporter creates an EventReporter that calls the handler for each event. The handler is called by the
+// goroutine that reported the event, so it should not block. The events that are not related to a ctx (e.g.
+// EventSignalReceived) are given with `context.Background()`.
+func NewEventReporter(handler func(context.Context, Event)) *EventReporter {
+	return &EventReporter{
+		handle: func(ctx context.Context, event Event) context.Context {
+			handler(ctx, event)
+			return ctx
+		},
+	}
+}
+
+// NewChannelReporter creates an EventReporter that sends each event to the channel. Sending blocks the goroutine that
+// reported the event, so the channel should be buffered and read continuously.
+func NewChannelReporter(ch chan<- Event) *EventReporter {
+	return NewEventReporter(func(_ context.Context, event Event) {
+		ch <- event
+	})
+}
+
+// report sets the time of the event and handles it.
+func (reporter *EventReporter) report(ctx context.Context, event Event) context.Context {
+	event.Time = time.Now()
+	return reporter.handle(ctx, event)
+}
+
+// BeforeStart reports an EventBeforeStart.
+func (reporter *EventReporter) BeforeStart(ctx context.Context, service Service) {
+	reporter.report(ctx, Event{Kind: EventBeforeStart, Service: service})
+}
+
+// AfterStart reports an EventAfterStart.
+func (reporter *EventReporter) AfterStart(ctx context.Context, service Service, err error) {
+	reporter.report(ctx, Event{Kind: EventAfterStart, Service: service, Err: err})
+}
+
+// BeforeStop reports an EventBeforeStop.
+func (reporter *EventReporter) BeforeStop(ctx context.Context, service Service) {
+	reporter.report(ctx, Event{Kind: EventBeforeStop, Service: service})
+}
+
+// AfterStop reports an EventAfterStop.
+func (reporter *EventReporter) AfterStop(ctx context.Context, service Service, err error) {
+	reporter.report(ctx, Event{Kind: EventAfterStop, Service: service, Err: err})
+}
+
+// BeforeLoad reports an EventBeforeLoad.
+func (reporter *EventReporter) BeforeLoad(ctx context.Context, configurable Configurable) {
+	service, _ := configurable.(Service)
+	reporter.report(ctx, Event{Kind: EventBeforeLoad, Service: service, configurable: configurable})
+}
+
+// AfterLoad reports an EventAfterLoad.
+func (reporter *EventReporter) AfterLoad(ctx context.Context, configurable Configurable, err error) {
+	service, _ := configurable.(Service)
+	reporter.report(ctx, Event{Kind: EventAfterLoad, Service: service, Err: err, configurable: configurable})
+}
+
+// SignalReceived reports an EventSignalReceived.
+func (reporter *EventReporter) SignalReceived(sig os.Signal) {
+	reporter.report(context.Background(), Event{Kind: EventSignalReceived, Signal: sig})
+}
+
+// BeforeRetry reports an EventBeforeRetry.
+func (reporter *EventReporter) BeforeRetry(ctx context.Context, service Service, attempt int) {
+	reporter.report(ctx, Event{Kind: EventBeforeRetry, Service: service, Attempt: attempt})
+}
+
+// AfterRetry reports an EventAfterRetry.
+func (reporter *EventReporter) AfterRetry(ctx context.Context, service Service, attempt int, err error, delay time.Duration) {
+	reporter.report(ctx, Event{Kind: EventAfterRetry, Service: service, Attempt: attempt, Err: err, Delay: delay})
+}
+
+// BeforeRestart reports an EventBeforeRestart.
+func (reporter *EventReporter) BeforeRestart(ctx context.Context, service Service, restarts int, err error) {
+	reporter.report(ctx, Event{Kind: EventBeforeRestart, Service: service, Attempt: restarts, Err: err})
+}
+
+// BeforeShutdownPhase reports an EventBeforeShutdownPhase.
+func (reporter *EventReporter) BeforeShutdownPhase(ctx context.Context, phase string) {
+	reporter.report(ctx, Event{Kind: EventBeforeShutdownPhase, Phase: phase})
+}
+
+// AfterShutdownPhase reports an EventAfterShutdownPhase.
+func (reporter *EventReporter) AfterShutdownPhase(ctx context.Context, phase string, err error) {
+	reporter.report(ctx, Event{Kind: EventAfterShutdownPhase, Phase: phase, Err: err})
+}
+
+// PanicRecovered reports an EventPanicRecovered.
+func (reporter *EventReporter) PanicRecovered(ctx context.Context, err *PanicError) {
+	reporter.report(ctx, Event{Kind: EventPanicRecovered, Service: err.Service, Err: err, Panic: err})
+}
+
+// BeforeReload reports an EventBeforeReload.
+func (reporter *EventReporter) BeforeReload(ctx context.Context, service Service) {
+	reporter.report(ctx, Event{Kind: EventBeforeReload, Service: service})
+}
+
+// AfterReload reports an EventAfterReload.
+func (reporter *EventReporter) AfterReload(ctx context.Context, service Service, err error) {
+	reporter.report(ctx, Event{Kind: EventAfterReload, Service: service, Err: err})
+}
+
+// BeforeRun reports an EventBeforeRun.
+func (reporter *EventReporter) BeforeRun(ctx context.Context, services []Service) context.Context {
+	return reporter.report(ctx, Event{Kind: EventBeforeRun, Services: services})
+}
+
+// AfterRun reports an EventAfterRun.
+func (reporter *EventReporter) AfterRun(ctx context.Context, err error) {
+	reporter.report(ctx, Event{Kind: EventAfterRun, Err: err})
+}
+
+// BeforeFinish reports an EventBeforeFinish.
+func (reporter *EventReporter) BeforeFinish(ctx context.Context) context.Context {
+	return reporter.report(ctx, Event{Kind: EventBeforeFinish})
+}
+
+// AfterFinish reports an EventAfterFinish.
+func (reporter *EventReporter) AfterFinish(ctx context.Context, err error) {
+	reporter.report(ctx, Event{Kind: EventAfterFinish, Err: err})
+}
+
+// dispatch calls the method of the reporter that originated the event, if the reporter implements it. It returns the
+// ctx returned by RunReporter.BeforeRun and RunReporter.BeforeFinish, otherwise the given one.
+func dispatch(ctx context.Context, reporter Reporter, event Event) context.Context {
+	switch event.Kind {
+	case EventBeforeStart:
+		reporter.BeforeStart(ctx, event.Service)
+	case EventAfterStart:
+		reporter.AfterStart(ctx, event.Service, event.Err)
+	case EventBeforeStop:
+		reporter.BeforeStop(ctx, event.Service)
+	case EventAfterStop:
+		reporter.AfterStop(ctx, event.Service, event.Err)
+	case EventBeforeLoad:
+		reporter.BeforeLoad(ctx, event.configurable)
+	case EventAfterLoad:
+		reporter.AfterLoad(ctx, event.configurable, event.Err)
+	case EventSignalReceived:
+		reporter.SignalReceived(event.Signal)
+	case EventBeforeRetry:
+		if r, ok := reporter.(RetrierReporter); ok {
+			r.BeforeRetry(ctx, event.Service, event.Attempt)
+		}
+	case EventAfterRetry:
+		if r, ok := reporter.(AttemptReporter); ok {
+			r.AfterRetry(ctx, event.Service, event.Attempt, event.Err, event.Delay)
+		}
+	case EventBeforeRestart:
+		if r, ok := reporter.(SupervisorReporter); ok {
+			r.BeforeRestart(ctx, event.Service, event.Attempt, event.Err)
+		}
+	case EventBeforeShutdownPhase:
+		if r, ok := reporter.(PhaseReporter); ok {
+			r.BeforeShutdownPhase(ctx, event.Phase)
+		}
+	case EventAfterShutdownPhase:
+		if r, ok := reporter.(PhaseReporter); ok {
+			r.AfterShutdownPhase(ctx, event.Phase, event.Err)
+		}
+	case EventPanicRecovered:
+		if r, ok := reporter.(PanicReporter); ok {
+			r.PanicRecovered(ctx, event.Panic)
+		}
+	case EventBeforeReload:
+		if r, ok := reporter.(ReloadReporter); ok {
+			r.BeforeReload(ctx, event.Service)
+		}
+	case EventAfterReload:
+		if r, ok := reporter.(ReloadReporter); ok {
+			r.AfterReload(ctx, event.Service, event.Err)
+		}
+	case EventBeforeRun:
+		if r, ok := reporter.(RunReporter); ok {
+			return r.BeforeRun(ctx, event.Services)
+		}
+	case EventAfterRun:
+		if r, ok := reporter.(RunReporter); ok {
+			r.AfterRun(ctx, event.Err)
+		}
+	case EventBeforeFinish:
+		if r, ok := reporter.(RunReporter); ok {
+			return r.BeforeFinish(ctx)
+		}
+	case EventAfterFinish:
+		if r, ok := reporter.(RunReporter); ok {
+			r.AfterFinish(ctx, event.Err)
+		}
+	}
+	return ctx
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/setare/go-services"
@@ -59,7 +60,7 @@ type server struct {
 }
 
 func (s *server) Name() string {
-	return s.Name()
+	return s.name
 }
 
 func (s *server) Listen(ctx context.Context) error {
@@ -87,16 +88,12 @@ func (s *server) Close(ctx context.Context) error {
 }
 
 func main() {
-	ctx := context.Background()
-
-	runner := services.NewRunner()
-	defer runner.Finish(ctx)
-
-	err := runner.Run(ctx, &service1{}, &service2{})
-	if err != nil {
-		panic(err)
-	}
-
 	fmt.Println("[hit Ctrl+C] to finish ...")
-	runner.Run(ctx, &server{name: "Server A"}, &server{name: "Server B"})
+	os.Exit(services.Main(
+		context.Background(),
+		nil,
+		[]services.Service{&service1{}, &service2{}},
+		&server{name: "Server A"},
+		&server{name: "Server B"},
+	))
 }
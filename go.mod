@@ -1,15 +1,24 @@
 module github.com/setare/go-services
 
-go 1.16
+go 1.21
 
 require (
 	github.com/cenkalti/backoff/v4 v4.1.1
 	github.com/golang/mock v1.6.0
 	github.com/jamillosantos/go-os-signals v0.2.0
-	github.com/kr/pretty v0.1.0 // indirect
 	github.com/onsi/ginkgo v1.16.4
 	github.com/onsi/gomega v1.14.0
 	github.com/setare/go-errors v0.0.0-20210713014844-e732b1a37dfd
+	gopkg.in/yaml.v2 v2.4.0
+)
+
+require (
+	github.com/fsnotify/fsnotify v1.4.9 // indirect
+	github.com/kr/pretty v0.1.0 // indirect
+	github.com/nxadm/tail v1.4.8 // indirect
+	golang.org/x/net v0.0.0-20210428140749-89ef3d95e781 // indirect
 	golang.org/x/sys v0.0.0-20210630005230-0f9fa26af87c // indirect
+	golang.org/x/text v0.3.6 // indirect
 	gopkg.in/check.v1 v1.0.0-20190902080502-41f04d3bba15 // indirect
+	gopkg.in/tomb.v1 v1.0.0-20141024135613-dd632973f1e7 // indirect
 )
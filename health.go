@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// HealthChecker describes a service that is able to check its own health.
+//
+// This interface is used by `Runner.Health`. For each started Resource and each listening Server, the Runner will call
+// `Check` and aggregate the results into a HealthReport.
+type HealthChecker interface {
+	// Check returns nil when the service is healthy. Otherwise, the error describes the problem.
+	Check(ctx context.Context) error
+}
+
+// HealthStatus is the result of the health check of a service.
+type HealthStatus string
+
+const (
+	// HealthStatusUnknown is the status of the services that do not implement HealthChecker.
+	HealthStatusUnknown HealthStatus = "unknown"
+	// HealthStatusHealthy is the status of the services whose HealthChecker.Check succeeded.
+	HealthStatusHealthy HealthStatus = "healthy"
+	// HealthStatusUnhealthy is the status of the services whose HealthChecker.Check failed.
+	HealthStatusUnhealthy HealthStatus = "unhealthy"
+)
+
+// ServiceHealth is the health of a single service.
+type ServiceHealth struct {
+	// Name is the name of the service (check Service.Name).
+	Name string
+	// Status is the result of the last check.
+	Status HealthStatus
+	// LastError is the error returned by the last check. It is nil when the service is healthy.
+	LastError error
+	// Latency is how long the last check took.
+	Latency time.Duration
+	// CheckedAt is when the last check was started.
+	CheckedAt time.Time
+	// LastHealthyAt is when the service was last reported healthy. It is zero if the service was never healthy.
+	LastHealthyAt time.Time
+}
+
+// HealthReport is the aggregated health of all services managed by a Runner.
+type HealthReport struct {
+	// Live is true when no service is unhealthy.
+	Live bool
+	// Ready is true when the service is live and all Runner.Run calls have finished starting their services.
+	Ready bool
+	// CheckedAt is when the report was generated.
+	CheckedAt time.Time
+	// Services is the health of each service, in the order they were started.
+	Services []ServiceHealth
+}
+
+// Health checks the health of all started Resource and all listening Server instances. The checks run in parallel
+// and the returned report is in the same order the services were started.
+//
+// Services that do not implement HealthChecker are reported with HealthStatusUnknown and do not affect the liveness.
+// The readiness is only true after all Runner.Run calls have finished starting their services, and until the Runner
+// starts shutting them down.
+func (r *Runner) Health(ctx context.Context) HealthReport {
+	r.mutex.Lock()
+	services := make([]Service, 0, len(r.resourceServices)+len(r.serverServices))
+	for _, resource := range r.resourceServices {
+		services = append(services, resource)
+	}
+	for _, server := range r.serverServices {
+		services = append(services, server)
+	}
+	ready := r.ready
+	r.mutex.Unlock()
+
+	report := HealthReport{
+		Live:      true,
+		CheckedAt: time.Now(),
+		Services:  make([]ServiceHealth, len(services)),
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(len(services))
+	for idx, service := range services {
+		go func(idx int, service Service) {
+			defer wg.Done()
+			report.Services[idx] = r.checkService(ctx, service)
+		}(idx, service)
+	}
+	wg.Wait()
+
+	for _, serviceHealth := range report.Services {
+		if serviceHealth.Status == HealthStatusUnhealthy {
+			report.Live = false
+		}
+	}
+	report.Ready = ready && report.Live
+	return report
+}
+
+// checkService runs the check of a single service updating the LastHealthyAt.
+func (r *Runner) checkService(ctx context.Context, service Service) ServiceHealth {
+	health := ServiceHealth{
+		Name:   service.Name(),
+		Status: HealthStatusUnknown,
+	}
+
+	checker, ok := As[HealthChecker](service)
+	if !ok {
+		return health
+	}
+
+	health.CheckedAt = time.Now()
+	health.LastError = checker.Check(ctx)
+	health.Latency = time.Since(health.CheckedAt)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if health.LastError == nil {
+		health.Status = HealthStatusHealthy
+		r.lastHealthyAt[service] = health.CheckedAt
+	} else {
+		health.Status = HealthStatusUnhealthy
+	}
+	health.LastHealthyAt = r.lastHealthyAt[service]
+	return health
+}
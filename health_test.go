@@ -0,0 +1,151 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/setare/go-services"
+)
+
+type checkedResource struct {
+	*MockResource
+	*MockHealthChecker
+}
+
+func newCheckedResource(ctrl *gomock.Controller, name string) *checkedResource {
+	r := &checkedResource{
+		MockResource:      NewMockResource(ctrl),
+		MockHealthChecker: NewMockHealthChecker(ctrl),
+	}
+	r.MockResource.EXPECT().Name().Return(name).AnyTimes()
+	return r
+}
+
+var _ = Describe("Health", func() {
+	It("should report the health of each started Resource", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		// 1. Create 3 resourceServices, only 2 of them can be checked.
+		serviceA := newCheckedResource(ctrl, "Service A")
+		serviceB := newCheckedResource(ctrl, "Service B")
+		serviceC := NewMockResource(ctrl)
+		serviceC.EXPECT().Name().Return("Service C").AnyTimes()
+
+		serviceA.MockResource.EXPECT().Start(gomock.Any())
+		serviceB.MockResource.EXPECT().Start(gomock.Any())
+		serviceC.EXPECT().Start(gomock.Any())
+
+		errB := errors.New("connection lost")
+		serviceA.MockHealthChecker.EXPECT().Check(gomock.Any()).Do(func(context.Context) {
+			time.Sleep(time.Millisecond * 10)
+		})
+		serviceB.MockHealthChecker.EXPECT().Check(gomock.Any()).Return(errB)
+
+		// 2. Start the services and check them.
+		runner := services.NewRunner()
+		Expect(runner.Run(ctx, serviceA, serviceB, serviceC)).To(Succeed())
+
+		report := runner.Health(ctx)
+		Expect(report.Live).To(BeFalse())
+		Expect(report.Ready).To(BeFalse())
+		Expect(report.Services).To(HaveLen(3))
+
+		Expect(report.Services[0].Name).To(Equal("Service A"))
+		Expect(report.Services[0].Status).To(Equal(services.HealthStatusHealthy))
+		Expect(report.Services[0].LastError).ToNot(HaveOccurred())
+		Expect(report.Services[0].Latency).To(BeNumerically(">=", time.Millisecond*10))
+		Expect(report.Services[0].LastHealthyAt).To(Equal(report.Services[0].CheckedAt))
+
+		Expect(report.Services[1].Name).To(Equal("Service B"))
+		Expect(report.Services[1].Status).To(Equal(services.HealthStatusUnhealthy))
+		Expect(report.Services[1].LastError).To(MatchError(errB))
+		Expect(report.Services[1].LastHealthyAt.IsZero()).To(BeTrue())
+
+		Expect(report.Services[2].Name).To(Equal("Service C"))
+		Expect(report.Services[2].Status).To(Equal(services.HealthStatusUnknown))
+	})
+
+	It("should be ready only after the services are started", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		serviceA := newCheckedResource(ctrl, "Service A")
+		serviceA.MockHealthChecker.EXPECT().Check(gomock.Any()).AnyTimes()
+
+		startReleased := make(chan struct{})
+		gomock.InOrder(
+			serviceA.MockResource.EXPECT().Start(gomock.Any()).Do(func(context.Context) {
+				<-startReleased
+			}),
+			serviceA.MockResource.EXPECT().Stop(gomock.Any()),
+		)
+
+		runner := services.NewRunner()
+		Expect(runner.Health(ctx).Ready).To(BeFalse())
+
+		runErr := make(chan error)
+		go func() {
+			runErr <- runner.Run(ctx, serviceA)
+		}()
+
+		// While starting, the runner is live but not ready.
+		time.Sleep(time.Millisecond * 50)
+		report := runner.Health(ctx)
+		Expect(report.Live).To(BeTrue())
+		Expect(report.Ready).To(BeFalse())
+
+		close(startReleased)
+		Expect(<-runErr).To(Succeed())
+
+		report = runner.Health(ctx)
+		Expect(report.Live).To(BeTrue())
+		Expect(report.Ready).To(BeTrue())
+		Expect(report.Services).To(HaveLen(1))
+
+		// After finishing, the runner is not ready anymore.
+		Expect(runner.Finish(ctx)).To(Succeed())
+		report = runner.Health(ctx)
+		Expect(report.Ready).To(BeFalse())
+		Expect(report.Services).To(BeEmpty())
+	})
+
+	It("should report listening Server instances", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx, cancelFunc := context.WithCancel(context.TODO())
+		defer cancelFunc()
+
+		serverA := NewMockServer(ctrl)
+		serverA.EXPECT().Name().Return("Server A").AnyTimes()
+		serverA.EXPECT().Listen(gomock.Any()).Do(func(ctx context.Context) {
+			<-ctx.Done()
+		})
+		serverA.EXPECT().Close(gomock.Any())
+
+		runner := services.NewRunner()
+		go func() {
+			defer GinkgoRecover()
+
+			time.Sleep(time.Millisecond * 50)
+			report := runner.Health(ctx)
+			Expect(report.Ready).To(BeTrue())
+			Expect(report.Services).To(HaveLen(1))
+			Expect(report.Services[0].Name).To(Equal("Server A"))
+			cancelFunc()
+		}()
+
+		Expect(runner.Run(ctx, serverA)).To(MatchError(context.Canceled))
+		Expect(runner.Health(context.TODO()).Services).To(BeEmpty())
+	})
+})
@@ -0,0 +1,9 @@
+package httpserver
+
+import "github.com/setare/go-services"
+
+const (
+	// ErrAlreadyListening is returned when Server.Listen is called while the server is already listening. It is the
+	// same as services.ErrAlreadyListening.
+	ErrAlreadyListening = services.ErrAlreadyListening
+)
@@ -0,0 +1,151 @@
+// Package httpserver implements a services.Server that wraps a `*http.Server`.
+package httpserver
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+)
+
+// Server wraps a `*http.Server` implementing the services.Server and services.ReadyNotifier interfaces.
+type Server struct {
+	name   string
+	server *http.Server
+
+	listener    net.Listener
+	tlsCertFile string
+	tlsKeyFile  string
+	tls         bool
+
+	mutex     sync.Mutex
+	listening bool
+	addr      net.Addr
+	ready     chan struct{}
+	readyOnce sync.Once
+}
+
+// Option configures a Server created by New.
+type Option = func(*Server)
+
+// WithTLS is an Option that makes the Server serve HTTPS using the given certificate and key files. If the
+// `http.Server.TLSConfig` already has the certificates, both files can be empty.
+func WithTLS(certFile, keyFile string) Option {
+	return func(server *Server) {
+		server.tls = true
+		server.tlsCertFile = certFile
+		server.tlsKeyFile = keyFile
+	}
+}
+
+// WithListener is an Option that makes the Server accept connections from the given listener, instead of binding to
+// the `http.Server.Addr`.
+func WithListener(listener net.Listener) Option {
+	return func(server *Server) {
+		server.listener = listener
+	}
+}
+
+// New creates a new Server with the given name that wraps the given `*http.Server`.
+func New(name string, server *http.Server, opts ...Option) *Server {
+	s := &Server{
+		name:   name,
+		server: server,
+		ready:  make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Name returns the name given when the Server was created.
+func (s *Server) Name() string {
+	return s.name
+}
+
+// Addr returns the address the Server is bound to. It is nil while the server is not listening.
+//
+// When the `http.Server.Addr` uses the port 0, this can be used to find which port was chosen.
+func (s *Server) Addr() net.Addr {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.addr
+}
+
+// Ready returns a channel that is closed when the Server is bound to its address (or has the listener given by
+// WithListener), so the services.Runner only considers it running after that.
+func (s *Server) Ready() <-chan struct{} {
+	return s.ready
+}
+
+// Listen binds the address (or uses the listener given by WithListener) and serves the requests. It blocks until the
+// server is closed. When the server is closed by Close, it returns nil.
+//
+// If the server is already listening, ErrAlreadyListening is returned.
+func (s *Server) Listen(_ context.Context) error {
+	s.mutex.Lock()
+	if s.listening {
+		s.mutex.Unlock()
+		return ErrAlreadyListening
+	}
+
+	listener := s.listener
+	if listener == nil {
+		addr := s.server.Addr
+		if addr == "" {
+			if s.tls {
+				addr = ":https"
+			} else {
+				addr = ":http"
+			}
+		}
+		var err error
+		listener, err = net.Listen("tcp", addr)
+		if err != nil {
+			s.mutex.Unlock()
+			return err
+		}
+	}
+	s.listening = true
+	s.addr = listener.Addr()
+	s.mutex.Unlock()
+	s.readyOnce.Do(func() {
+		close(s.ready)
+	})
+
+	defer func() {
+		s.mutex.Lock()
+		s.listening = false
+		s.addr = nil
+		s.mutex.Unlock()
+	}()
+
+	var err error
+	if s.tls {
+		err = s.server.ServeTLS(listener, s.tlsCertFile, s.tlsKeyFile)
+	} else {
+		err = s.server.Serve(listener)
+	}
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+// Close gracefully shuts down the server (check `http.Server.Shutdown`) waiting, at most, until the ctx is done. If
+// the graceful shutdown does not finish in time, the server is forcefully closed and the error of the graceful
+// shutdown is returned.
+//
+// If the server is not listening, nil is returned. Since a `*http.Server` cannot be reused after closed, further calls
+// to Listen will return immediately.
+func (s *Server) Close(ctx context.Context) error {
+	err := s.server.Shutdown(ctx)
+	if err != nil {
+		if closeErr := s.server.Close(); closeErr != nil {
+			return closeErr
+		}
+		return err
+	}
+	return nil
+}
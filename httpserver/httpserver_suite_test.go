@@ -0,0 +1,13 @@
+package httpserver_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestHTTPServer(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "HTTP Server Tests")
+}
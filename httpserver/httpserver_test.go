@@ -0,0 +1,196 @@
+package httpserver_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/setare/go-services"
+	"github.com/setare/go-services/httpserver"
+	"github.com/setare/go-services/servicestest"
+)
+
+var helloHandler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+	_, _ = w.Write([]byte("hello"))
+})
+
+func get(client *http.Client, url string) string {
+	resp, err := client.Get(url)
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	Expect(err).ToNot(HaveOccurred())
+	return string(body)
+}
+
+func listen(server *httpserver.Server) <-chan error {
+	listenErr := make(chan error, 1)
+	go func() {
+		listenErr <- server.Listen(context.TODO())
+	}()
+	Eventually(server.Addr).ShouldNot(BeNil())
+	return listenErr
+}
+
+var _ = Describe("Server", func() {
+	var _ services.Server = &httpserver.Server{}
+	var _ services.ReadyNotifier = &httpserver.Server{}
+
+	It("should serve requests and close gracefully", func() {
+		server := httpserver.New("HTTP", &http.Server{
+			Addr:    "127.0.0.1:0",
+			Handler: helloHandler,
+		})
+		Expect(server.Name()).To(Equal("HTTP"))
+		Expect(server.Addr()).To(BeNil())
+
+		listenErr := listen(server)
+		Expect(get(http.DefaultClient, "http://"+server.Addr().String())).To(Equal("hello"))
+
+		Expect(server.Close(context.TODO())).To(Succeed())
+		Eventually(listenErr).Should(Receive(BeNil()))
+		Expect(server.Addr()).To(BeNil())
+	})
+
+	It("should fail listening twice", func() {
+		server := httpserver.New("HTTP", &http.Server{
+			Addr:    "127.0.0.1:0",
+			Handler: helloHandler,
+		})
+		listenErr := listen(server)
+		defer func() {
+			Expect(server.Close(context.TODO())).To(Succeed())
+			Eventually(listenErr).Should(Receive(BeNil()))
+		}()
+
+		Expect(server.Listen(context.TODO())).To(MatchError(httpserver.ErrAlreadyListening))
+	})
+
+	It("should do nothing when closing a server that is not listening", func() {
+		server := httpserver.New("HTTP", &http.Server{
+			Addr: "127.0.0.1:0",
+		})
+		Expect(server.Close(context.TODO())).To(Succeed())
+	})
+
+	It("should serve using a pre-bound listener", func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).ToNot(HaveOccurred())
+
+		server := httpserver.New("HTTP", &http.Server{
+			Handler: helloHandler,
+		}, httpserver.WithListener(listener))
+		listenErr := listen(server)
+		Expect(server.Addr()).To(Equal(listener.Addr()))
+		Expect(get(http.DefaultClient, "http://"+listener.Addr().String())).To(Equal("hello"))
+
+		Expect(server.Close(context.TODO())).To(Succeed())
+		Eventually(listenErr).Should(Receive(BeNil()))
+	})
+
+	It("should serve using TLS", func() {
+		// httptest provides a certificate trusted by its client.
+		tlsServer := httptest.NewTLSServer(helloHandler)
+		defer tlsServer.Close()
+
+		server := httpserver.New("HTTPS", &http.Server{
+			Addr:      "127.0.0.1:0",
+			Handler:   helloHandler,
+			TLSConfig: tlsServer.TLS.Clone(),
+		}, httpserver.WithTLS("", ""))
+		listenErr := listen(server)
+		Expect(get(tlsServer.Client(), "https://"+server.Addr().String())).To(Equal("hello"))
+
+		Expect(server.Close(context.TODO())).To(Succeed())
+		Eventually(listenErr).Should(Receive(BeNil()))
+	})
+
+	It("should force closing when the graceful shutdown times out", func() {
+		requestStarted := make(chan struct{})
+		releaseRequest := make(chan struct{})
+		defer close(releaseRequest)
+
+		server := httpserver.New("HTTP", &http.Server{
+			Addr: "127.0.0.1:0",
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				close(requestStarted)
+				<-releaseRequest
+			}),
+		})
+		listenErr := listen(server)
+
+		go func() {
+			_, _ = http.Get("http://" + server.Addr().String())
+		}()
+		Eventually(requestStarted).Should(BeClosed())
+
+		ctx, cancelFunc := context.WithTimeout(context.TODO(), time.Millisecond*50)
+		defer cancelFunc()
+		Expect(server.Close(ctx)).To(MatchError(context.DeadlineExceeded))
+		Eventually(listenErr).Should(Receive(BeNil()))
+	})
+
+	It("should run within a Runner", func() {
+		server := httpserver.New("HTTP", &http.Server{
+			Addr:    "127.0.0.1:0",
+			Handler: helloHandler,
+		})
+
+		ctx, cancelFunc := context.WithCancel(context.TODO())
+		defer cancelFunc()
+
+		runErr := make(chan error, 1)
+		go func() {
+			runErr <- services.NewRunner().Run(ctx, server)
+		}()
+		Eventually(server.Addr).ShouldNot(BeNil())
+		Expect(get(http.DefaultClient, "http://"+server.Addr().String())).To(Equal("hello"))
+
+		cancelFunc()
+		Eventually(runErr).Should(Receive(MatchError(context.Canceled)))
+	})
+
+	It("should be ready once it is bound", func() {
+		server := httpserver.New("HTTP", &http.Server{
+			Addr:    "127.0.0.1:0",
+			Handler: helloHandler,
+		})
+		Consistently(server.Ready(), time.Millisecond*50).ShouldNot(BeClosed())
+
+		listenErr := listen(server)
+		Eventually(server.Ready()).Should(BeClosed())
+
+		Expect(server.Close(context.TODO())).To(Succeed())
+		Eventually(listenErr).Should(Receive(BeNil()))
+	})
+
+	It("should fail a Runner when the address is already in use", func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).ToNot(HaveOccurred())
+		defer listener.Close()
+
+		server := httpserver.New("HTTP", &http.Server{
+			Addr:    listener.Addr().String(),
+			Handler: helloHandler,
+		})
+
+		err = services.NewRunner().Run(context.TODO(), server)
+		Expect(err).To(MatchError(services.ErrExitedBeforeReady))
+	})
+})
+
+func TestVerifyServer(t *testing.T) {
+	servicestest.VerifyServer(t, func() services.Server {
+		return httpserver.New("HTTP", &http.Server{
+			Addr:    "127.0.0.1:0",
+			Handler: helloHandler,
+		})
+	})
+}
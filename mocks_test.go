@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/setare/go-services (interfaces: Resource,Server,Reporter,Configurable,RetrierReporter)
+// Source: github.com/setare/go-services (interfaces: Resource,Server,Reporter,Configurable,RetrierReporter,AttemptReporter,Dependent,HealthChecker,SupervisorReporter,ShutdownTimeouter,PhaseReporter,PanicReporter,Reloadable,ReloadReporter,RunReporter)
 
 // Package services_test is a generated GoMock package.
 package services_test
@@ -8,6 +8,7 @@ import (
 	context "context"
 	os "os"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	go_services "github.com/setare/go-services"
@@ -405,3 +406,941 @@ func (mr *MockRetrierReporterMockRecorder) SignalReceived(arg0 interface{}) *gom
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignalReceived", reflect.TypeOf((*MockRetrierReporter)(nil).SignalReceived), arg0)
 }
+
+// MockAttemptReporter is a mock of AttemptReporter interface.
+type MockAttemptReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttemptReporterMockRecorder
+}
+
+// MockAttemptReporterMockRecorder is the mock recorder for MockAttemptReporter.
+type MockAttemptReporterMockRecorder struct {
+	mock *MockAttemptReporter
+}
+
+// NewMockAttemptReporter creates a new mock instance.
+func NewMockAttemptReporter(ctrl *gomock.Controller) *MockAttemptReporter {
+	mock := &MockAttemptReporter{ctrl: ctrl}
+	mock.recorder = &MockAttemptReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttemptReporter) EXPECT() *MockAttemptReporterMockRecorder {
+	return m.recorder
+}
+
+// AfterLoad mocks base method.
+func (m *MockAttemptReporter) AfterLoad(arg0 context.Context, arg1 go_services.Configurable, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AfterLoad", arg0, arg1, arg2)
+}
+
+// AfterLoad indicates an expected call of AfterLoad.
+func (mr *MockAttemptReporterMockRecorder) AfterLoad(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AfterLoad", reflect.TypeOf((*MockAttemptReporter)(nil).AfterLoad), arg0, arg1, arg2)
+}
+
+// AfterRetry mocks base method.
+func (m *MockAttemptReporter) AfterRetry(arg0 context.Context, arg1 go_services.Service, arg2 int, arg3 error, arg4 time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AfterRetry", arg0, arg1, arg2, arg3, arg4)
+}
+
+// AfterRetry indicates an expected call of AfterRetry.
+func (mr *MockAttemptReporterMockRecorder) AfterRetry(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AfterRetry", reflect.TypeOf((*MockAttemptReporter)(nil).AfterRetry), arg0, arg1, arg2, arg3, arg4)
+}
+
+// AfterStart mocks base method.
+func (m *MockAttemptReporter) AfterStart(arg0 context.Context, arg1 go_services.Service, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AfterStart", arg0, arg1, arg2)
+}
+
+// AfterStart indicates an expected call of AfterStart.
+func (mr *MockAttemptReporterMockRecorder) AfterStart(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AfterStart", reflect.TypeOf((*MockAttemptReporter)(nil).AfterStart), arg0, arg1, arg2)
+}
+
+// AfterStop mocks base method.
+func (m *MockAttemptReporter) AfterStop(arg0 context.Context, arg1 go_services.Service, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AfterStop", arg0, arg1, arg2)
+}
+
+// AfterStop indicates an expected call of AfterStop.
+func (mr *MockAttemptReporterMockRecorder) AfterStop(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AfterStop", reflect.TypeOf((*MockAttemptReporter)(nil).AfterStop), arg0, arg1, arg2)
+}
+
+// BeforeLoad mocks base method.
+func (m *MockAttemptReporter) BeforeLoad(arg0 context.Context, arg1 go_services.Configurable) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BeforeLoad", arg0, arg1)
+}
+
+// BeforeLoad indicates an expected call of BeforeLoad.
+func (mr *MockAttemptReporterMockRecorder) BeforeLoad(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeforeLoad", reflect.TypeOf((*MockAttemptReporter)(nil).BeforeLoad), arg0, arg1)
+}
+
+// BeforeRetry mocks base method.
+func (m *MockAttemptReporter) BeforeRetry(arg0 context.Context, arg1 go_services.Service, arg2 int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BeforeRetry", arg0, arg1, arg2)
+}
+
+// BeforeRetry indicates an expected call of BeforeRetry.
+func (mr *MockAttemptReporterMockRecorder) BeforeRetry(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeforeRetry", reflect.TypeOf((*MockAttemptReporter)(nil).BeforeRetry), arg0, arg1, arg2)
+}
+
+// BeforeStart mocks base method.
+func (m *MockAttemptReporter) BeforeStart(arg0 context.Context, arg1 go_services.Service) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BeforeStart", arg0, arg1)
+}
+
+// BeforeStart indicates an expected call of BeforeStart.
+func (mr *MockAttemptReporterMockRecorder) BeforeStart(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeforeStart", reflect.TypeOf((*MockAttemptReporter)(nil).BeforeStart), arg0, arg1)
+}
+
+// BeforeStop mocks base method.
+func (m *MockAttemptReporter) BeforeStop(arg0 context.Context, arg1 go_services.Service) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BeforeStop", arg0, arg1)
+}
+
+// BeforeStop indicates an expected call of BeforeStop.
+func (mr *MockAttemptReporterMockRecorder) BeforeStop(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeforeStop", reflect.TypeOf((*MockAttemptReporter)(nil).BeforeStop), arg0, arg1)
+}
+
+// SignalReceived mocks base method.
+func (m *MockAttemptReporter) SignalReceived(arg0 os.Signal) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SignalReceived", arg0)
+}
+
+// SignalReceived indicates an expected call of SignalReceived.
+func (mr *MockAttemptReporterMockRecorder) SignalReceived(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignalReceived", reflect.TypeOf((*MockAttemptReporter)(nil).SignalReceived), arg0)
+}
+
+// MockDependent is a mock of Dependent interface.
+type MockDependent struct {
+	ctrl     *gomock.Controller
+	recorder *MockDependentMockRecorder
+}
+
+// MockDependentMockRecorder is the mock recorder for MockDependent.
+type MockDependentMockRecorder struct {
+	mock *MockDependent
+}
+
+// NewMockDependent creates a new mock instance.
+func NewMockDependent(ctrl *gomock.Controller) *MockDependent {
+	mock := &MockDependent{ctrl: ctrl}
+	mock.recorder = &MockDependentMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDependent) EXPECT() *MockDependentMockRecorder {
+	return m.recorder
+}
+
+// DependsOn mocks base method.
+func (m *MockDependent) DependsOn() []go_services.Service {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DependsOn")
+	ret0, _ := ret[0].([]go_services.Service)
+	return ret0
+}
+
+// DependsOn indicates an expected call of DependsOn.
+func (mr *MockDependentMockRecorder) DependsOn() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DependsOn", reflect.TypeOf((*MockDependent)(nil).DependsOn))
+}
+
+// MockHealthChecker is a mock of HealthChecker interface.
+type MockHealthChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockHealthCheckerMockRecorder
+}
+
+// MockHealthCheckerMockRecorder is the mock recorder for MockHealthChecker.
+type MockHealthCheckerMockRecorder struct {
+	mock *MockHealthChecker
+}
+
+// NewMockHealthChecker creates a new mock instance.
+func NewMockHealthChecker(ctrl *gomock.Controller) *MockHealthChecker {
+	mock := &MockHealthChecker{ctrl: ctrl}
+	mock.recorder = &MockHealthCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHealthChecker) EXPECT() *MockHealthCheckerMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockHealthChecker) Check(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockHealthCheckerMockRecorder) Check(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockHealthChecker)(nil).Check), arg0)
+}
+
+// MockSupervisorReporter is a mock of SupervisorReporter interface.
+type MockSupervisorReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockSupervisorReporterMockRecorder
+}
+
+// MockSupervisorReporterMockRecorder is the mock recorder for MockSupervisorReporter.
+type MockSupervisorReporterMockRecorder struct {
+	mock *MockSupervisorReporter
+}
+
+// NewMockSupervisorReporter creates a new mock instance.
+func NewMockSupervisorReporter(ctrl *gomock.Controller) *MockSupervisorReporter {
+	mock := &MockSupervisorReporter{ctrl: ctrl}
+	mock.recorder = &MockSupervisorReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSupervisorReporter) EXPECT() *MockSupervisorReporterMockRecorder {
+	return m.recorder
+}
+
+// AfterLoad mocks base method.
+func (m *MockSupervisorReporter) AfterLoad(arg0 context.Context, arg1 go_services.Configurable, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AfterLoad", arg0, arg1, arg2)
+}
+
+// AfterLoad indicates an expected call of AfterLoad.
+func (mr *MockSupervisorReporterMockRecorder) AfterLoad(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AfterLoad", reflect.TypeOf((*MockSupervisorReporter)(nil).AfterLoad), arg0, arg1, arg2)
+}
+
+// AfterStart mocks base method.
+func (m *MockSupervisorReporter) AfterStart(arg0 context.Context, arg1 go_services.Service, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AfterStart", arg0, arg1, arg2)
+}
+
+// AfterStart indicates an expected call of AfterStart.
+func (mr *MockSupervisorReporterMockRecorder) AfterStart(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AfterStart", reflect.TypeOf((*MockSupervisorReporter)(nil).AfterStart), arg0, arg1, arg2)
+}
+
+// AfterStop mocks base method.
+func (m *MockSupervisorReporter) AfterStop(arg0 context.Context, arg1 go_services.Service, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AfterStop", arg0, arg1, arg2)
+}
+
+// AfterStop indicates an expected call of AfterStop.
+func (mr *MockSupervisorReporterMockRecorder) AfterStop(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AfterStop", reflect.TypeOf((*MockSupervisorReporter)(nil).AfterStop), arg0, arg1, arg2)
+}
+
+// BeforeLoad mocks base method.
+func (m *MockSupervisorReporter) BeforeLoad(arg0 context.Context, arg1 go_services.Configurable) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BeforeLoad", arg0, arg1)
+}
+
+// BeforeLoad indicates an expected call of BeforeLoad.
+func (mr *MockSupervisorReporterMockRecorder) BeforeLoad(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeforeLoad", reflect.TypeOf((*MockSupervisorReporter)(nil).BeforeLoad), arg0, arg1)
+}
+
+// BeforeRestart mocks base method.
+func (m *MockSupervisorReporter) BeforeRestart(arg0 context.Context, arg1 go_services.Service, arg2 int, arg3 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BeforeRestart", arg0, arg1, arg2, arg3)
+}
+
+// BeforeRestart indicates an expected call of BeforeRestart.
+func (mr *MockSupervisorReporterMockRecorder) BeforeRestart(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeforeRestart", reflect.TypeOf((*MockSupervisorReporter)(nil).BeforeRestart), arg0, arg1, arg2, arg3)
+}
+
+// BeforeStart mocks base method.
+func (m *MockSupervisorReporter) BeforeStart(arg0 context.Context, arg1 go_services.Service) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BeforeStart", arg0, arg1)
+}
+
+// BeforeStart indicates an expected call of BeforeStart.
+func (mr *MockSupervisorReporterMockRecorder) BeforeStart(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeforeStart", reflect.TypeOf((*MockSupervisorReporter)(nil).BeforeStart), arg0, arg1)
+}
+
+// BeforeStop mocks base method.
+func (m *MockSupervisorReporter) BeforeStop(arg0 context.Context, arg1 go_services.Service) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BeforeStop", arg0, arg1)
+}
+
+// BeforeStop indicates an expected call of BeforeStop.
+func (mr *MockSupervisorReporterMockRecorder) BeforeStop(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeforeStop", reflect.TypeOf((*MockSupervisorReporter)(nil).BeforeStop), arg0, arg1)
+}
+
+// SignalReceived mocks base method.
+func (m *MockSupervisorReporter) SignalReceived(arg0 os.Signal) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SignalReceived", arg0)
+}
+
+// SignalReceived indicates an expected call of SignalReceived.
+func (mr *MockSupervisorReporterMockRecorder) SignalReceived(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignalReceived", reflect.TypeOf((*MockSupervisorReporter)(nil).SignalReceived), arg0)
+}
+
+// MockShutdownTimeouter is a mock of ShutdownTimeouter interface.
+type MockShutdownTimeouter struct {
+	ctrl     *gomock.Controller
+	recorder *MockShutdownTimeouterMockRecorder
+}
+
+// MockShutdownTimeouterMockRecorder is the mock recorder for MockShutdownTimeouter.
+type MockShutdownTimeouterMockRecorder struct {
+	mock *MockShutdownTimeouter
+}
+
+// NewMockShutdownTimeouter creates a new mock instance.
+func NewMockShutdownTimeouter(ctrl *gomock.Controller) *MockShutdownTimeouter {
+	mock := &MockShutdownTimeouter{ctrl: ctrl}
+	mock.recorder = &MockShutdownTimeouterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShutdownTimeouter) EXPECT() *MockShutdownTimeouterMockRecorder {
+	return m.recorder
+}
+
+// ShutdownTimeout mocks base method.
+func (m *MockShutdownTimeouter) ShutdownTimeout() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShutdownTimeout")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// ShutdownTimeout indicates an expected call of ShutdownTimeout.
+func (mr *MockShutdownTimeouterMockRecorder) ShutdownTimeout() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShutdownTimeout", reflect.TypeOf((*MockShutdownTimeouter)(nil).ShutdownTimeout))
+}
+
+// MockPhaseReporter is a mock of PhaseReporter interface.
+type MockPhaseReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockPhaseReporterMockRecorder
+}
+
+// MockPhaseReporterMockRecorder is the mock recorder for MockPhaseReporter.
+type MockPhaseReporterMockRecorder struct {
+	mock *MockPhaseReporter
+}
+
+// NewMockPhaseReporter creates a new mock instance.
+func NewMockPhaseReporter(ctrl *gomock.Controller) *MockPhaseReporter {
+	mock := &MockPhaseReporter{ctrl: ctrl}
+	mock.recorder = &MockPhaseReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPhaseReporter) EXPECT() *MockPhaseReporterMockRecorder {
+	return m.recorder
+}
+
+// AfterLoad mocks base method.
+func (m *MockPhaseReporter) AfterLoad(arg0 context.Context, arg1 go_services.Configurable, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AfterLoad", arg0, arg1, arg2)
+}
+
+// AfterLoad indicates an expected call of AfterLoad.
+func (mr *MockPhaseReporterMockRecorder) AfterLoad(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AfterLoad", reflect.TypeOf((*MockPhaseReporter)(nil).AfterLoad), arg0, arg1, arg2)
+}
+
+// AfterShutdownPhase mocks base method.
+func (m *MockPhaseReporter) AfterShutdownPhase(arg0 context.Context, arg1 string, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AfterShutdownPhase", arg0, arg1, arg2)
+}
+
+// AfterShutdownPhase indicates an expected call of AfterShutdownPhase.
+func (mr *MockPhaseReporterMockRecorder) AfterShutdownPhase(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AfterShutdownPhase", reflect.TypeOf((*MockPhaseReporter)(nil).AfterShutdownPhase), arg0, arg1, arg2)
+}
+
+// AfterStart mocks base method.
+func (m *MockPhaseReporter) AfterStart(arg0 context.Context, arg1 go_services.Service, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AfterStart", arg0, arg1, arg2)
+}
+
+// AfterStart indicates an expected call of AfterStart.
+func (mr *MockPhaseReporterMockRecorder) AfterStart(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AfterStart", reflect.TypeOf((*MockPhaseReporter)(nil).AfterStart), arg0, arg1, arg2)
+}
+
+// AfterStop mocks base method.
+func (m *MockPhaseReporter) AfterStop(arg0 context.Context, arg1 go_services.Service, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AfterStop", arg0, arg1, arg2)
+}
+
+// AfterStop indicates an expected call of AfterStop.
+func (mr *MockPhaseReporterMockRecorder) AfterStop(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AfterStop", reflect.TypeOf((*MockPhaseReporter)(nil).AfterStop), arg0, arg1, arg2)
+}
+
+// BeforeLoad mocks base method.
+func (m *MockPhaseReporter) BeforeLoad(arg0 context.Context, arg1 go_services.Configurable) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BeforeLoad", arg0, arg1)
+}
+
+// BeforeLoad indicates an expected call of BeforeLoad.
+func (mr *MockPhaseReporterMockRecorder) BeforeLoad(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeforeLoad", reflect.TypeOf((*MockPhaseReporter)(nil).BeforeLoad), arg0, arg1)
+}
+
+// BeforeShutdownPhase mocks base method.
+func (m *MockPhaseReporter) BeforeShutdownPhase(arg0 context.Context, arg1 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BeforeShutdownPhase", arg0, arg1)
+}
+
+// BeforeShutdownPhase indicates an expected call of BeforeShutdownPhase.
+func (mr *MockPhaseReporterMockRecorder) BeforeShutdownPhase(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeforeShutdownPhase", reflect.TypeOf((*MockPhaseReporter)(nil).BeforeShutdownPhase), arg0, arg1)
+}
+
+// BeforeStart mocks base method.
+func (m *MockPhaseReporter) BeforeStart(arg0 context.Context, arg1 go_services.Service) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BeforeStart", arg0, arg1)
+}
+
+// BeforeStart indicates an expected call of BeforeStart.
+func (mr *MockPhaseReporterMockRecorder) BeforeStart(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeforeStart", reflect.TypeOf((*MockPhaseReporter)(nil).BeforeStart), arg0, arg1)
+}
+
+// BeforeStop mocks base method.
+func (m *MockPhaseReporter) BeforeStop(arg0 context.Context, arg1 go_services.Service) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BeforeStop", arg0, arg1)
+}
+
+// BeforeStop indicates an expected call of BeforeStop.
+func (mr *MockPhaseReporterMockRecorder) BeforeStop(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeforeStop", reflect.TypeOf((*MockPhaseReporter)(nil).BeforeStop), arg0, arg1)
+}
+
+// SignalReceived mocks base method.
+func (m *MockPhaseReporter) SignalReceived(arg0 os.Signal) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SignalReceived", arg0)
+}
+
+// SignalReceived indicates an expected call of SignalReceived.
+func (mr *MockPhaseReporterMockRecorder) SignalReceived(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignalReceived", reflect.TypeOf((*MockPhaseReporter)(nil).SignalReceived), arg0)
+}
+
+// MockPanicReporter is a mock of PanicReporter interface.
+type MockPanicReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockPanicReporterMockRecorder
+}
+
+// MockPanicReporterMockRecorder is the mock recorder for MockPanicReporter.
+type MockPanicReporterMockRecorder struct {
+	mock *MockPanicReporter
+}
+
+// NewMockPanicReporter creates a new mock instance.
+func NewMockPanicReporter(ctrl *gomock.Controller) *MockPanicReporter {
+	mock := &MockPanicReporter{ctrl: ctrl}
+	mock.recorder = &MockPanicReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPanicReporter) EXPECT() *MockPanicReporterMockRecorder {
+	return m.recorder
+}
+
+// AfterLoad mocks base method.
+func (m *MockPanicReporter) AfterLoad(arg0 context.Context, arg1 go_services.Configurable, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AfterLoad", arg0, arg1, arg2)
+}
+
+// AfterLoad indicates an expected call of AfterLoad.
+func (mr *MockPanicReporterMockRecorder) AfterLoad(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AfterLoad", reflect.TypeOf((*MockPanicReporter)(nil).AfterLoad), arg0, arg1, arg2)
+}
+
+// AfterStart mocks base method.
+func (m *MockPanicReporter) AfterStart(arg0 context.Context, arg1 go_services.Service, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AfterStart", arg0, arg1, arg2)
+}
+
+// AfterStart indicates an expected call of AfterStart.
+func (mr *MockPanicReporterMockRecorder) AfterStart(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AfterStart", reflect.TypeOf((*MockPanicReporter)(nil).AfterStart), arg0, arg1, arg2)
+}
+
+// AfterStop mocks base method.
+func (m *MockPanicReporter) AfterStop(arg0 context.Context, arg1 go_services.Service, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AfterStop", arg0, arg1, arg2)
+}
+
+// AfterStop indicates an expected call of AfterStop.
+func (mr *MockPanicReporterMockRecorder) AfterStop(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AfterStop", reflect.TypeOf((*MockPanicReporter)(nil).AfterStop), arg0, arg1, arg2)
+}
+
+// BeforeLoad mocks base method.
+func (m *MockPanicReporter) BeforeLoad(arg0 context.Context, arg1 go_services.Configurable) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BeforeLoad", arg0, arg1)
+}
+
+// BeforeLoad indicates an expected call of BeforeLoad.
+func (mr *MockPanicReporterMockRecorder) BeforeLoad(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeforeLoad", reflect.TypeOf((*MockPanicReporter)(nil).BeforeLoad), arg0, arg1)
+}
+
+// BeforeStart mocks base method.
+func (m *MockPanicReporter) BeforeStart(arg0 context.Context, arg1 go_services.Service) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BeforeStart", arg0, arg1)
+}
+
+// BeforeStart indicates an expected call of BeforeStart.
+func (mr *MockPanicReporterMockRecorder) BeforeStart(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeforeStart", reflect.TypeOf((*MockPanicReporter)(nil).BeforeStart), arg0, arg1)
+}
+
+// BeforeStop mocks base method.
+func (m *MockPanicReporter) BeforeStop(arg0 context.Context, arg1 go_services.Service) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BeforeStop", arg0, arg1)
+}
+
+// BeforeStop indicates an expected call of BeforeStop.
+func (mr *MockPanicReporterMockRecorder) BeforeStop(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeforeStop", reflect.TypeOf((*MockPanicReporter)(nil).BeforeStop), arg0, arg1)
+}
+
+// PanicRecovered mocks base method.
+func (m *MockPanicReporter) PanicRecovered(arg0 context.Context, arg1 *go_services.PanicError) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "PanicRecovered", arg0, arg1)
+}
+
+// PanicRecovered indicates an expected call of PanicRecovered.
+func (mr *MockPanicReporterMockRecorder) PanicRecovered(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PanicRecovered", reflect.TypeOf((*MockPanicReporter)(nil).PanicRecovered), arg0, arg1)
+}
+
+// SignalReceived mocks base method.
+func (m *MockPanicReporter) SignalReceived(arg0 os.Signal) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SignalReceived", arg0)
+}
+
+// SignalReceived indicates an expected call of SignalReceived.
+func (mr *MockPanicReporterMockRecorder) SignalReceived(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignalReceived", reflect.TypeOf((*MockPanicReporter)(nil).SignalReceived), arg0)
+}
+
+// MockReloadable is a mock of Reloadable interface.
+type MockReloadable struct {
+	ctrl     *gomock.Controller
+	recorder *MockReloadableMockRecorder
+}
+
+// MockReloadableMockRecorder is the mock recorder for MockReloadable.
+type MockReloadableMockRecorder struct {
+	mock *MockReloadable
+}
+
+// NewMockReloadable creates a new mock instance.
+func NewMockReloadable(ctrl *gomock.Controller) *MockReloadable {
+	mock := &MockReloadable{ctrl: ctrl}
+	mock.recorder = &MockReloadableMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReloadable) EXPECT() *MockReloadableMockRecorder {
+	return m.recorder
+}
+
+// Reload mocks base method.
+func (m *MockReloadable) Reload(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reload", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reload indicates an expected call of Reload.
+func (mr *MockReloadableMockRecorder) Reload(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reload", reflect.TypeOf((*MockReloadable)(nil).Reload), arg0)
+}
+
+// MockReloadReporter is a mock of ReloadReporter interface.
+type MockReloadReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockReloadReporterMockRecorder
+}
+
+// MockReloadReporterMockRecorder is the mock recorder for MockReloadReporter.
+type MockReloadReporterMockRecorder struct {
+	mock *MockReloadReporter
+}
+
+// NewMockReloadReporter creates a new mock instance.
+func NewMockReloadReporter(ctrl *gomock.Controller) *MockReloadReporter {
+	mock := &MockReloadReporter{ctrl: ctrl}
+	mock.recorder = &MockReloadReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReloadReporter) EXPECT() *MockReloadReporterMockRecorder {
+	return m.recorder
+}
+
+// AfterLoad mocks base method.
+func (m *MockReloadReporter) AfterLoad(arg0 context.Context, arg1 go_services.Configurable, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AfterLoad", arg0, arg1, arg2)
+}
+
+// AfterLoad indicates an expected call of AfterLoad.
+func (mr *MockReloadReporterMockRecorder) AfterLoad(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AfterLoad", reflect.TypeOf((*MockReloadReporter)(nil).AfterLoad), arg0, arg1, arg2)
+}
+
+// AfterReload mocks base method.
+func (m *MockReloadReporter) AfterReload(arg0 context.Context, arg1 go_services.Service, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AfterReload", arg0, arg1, arg2)
+}
+
+// AfterReload indicates an expected call of AfterReload.
+func (mr *MockReloadReporterMockRecorder) AfterReload(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AfterReload", reflect.TypeOf((*MockReloadReporter)(nil).AfterReload), arg0, arg1, arg2)
+}
+
+// AfterStart mocks base method.
+func (m *MockReloadReporter) AfterStart(arg0 context.Context, arg1 go_services.Service, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AfterStart", arg0, arg1, arg2)
+}
+
+// AfterStart indicates an expected call of AfterStart.
+func (mr *MockReloadReporterMockRecorder) AfterStart(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AfterStart", reflect.TypeOf((*MockReloadReporter)(nil).AfterStart), arg0, arg1, arg2)
+}
+
+// AfterStop mocks base method.
+func (m *MockReloadReporter) AfterStop(arg0 context.Context, arg1 go_services.Service, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AfterStop", arg0, arg1, arg2)
+}
+
+// AfterStop indicates an expected call of AfterStop.
+func (mr *MockReloadReporterMockRecorder) AfterStop(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AfterStop", reflect.TypeOf((*MockReloadReporter)(nil).AfterStop), arg0, arg1, arg2)
+}
+
+// BeforeLoad mocks base method.
+func (m *MockReloadReporter) BeforeLoad(arg0 context.Context, arg1 go_services.Configurable) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BeforeLoad", arg0, arg1)
+}
+
+// BeforeLoad indicates an expected call of BeforeLoad.
+func (mr *MockReloadReporterMockRecorder) BeforeLoad(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeforeLoad", reflect.TypeOf((*MockReloadReporter)(nil).BeforeLoad), arg0, arg1)
+}
+
+// BeforeReload mocks base method.
+func (m *MockReloadReporter) BeforeReload(arg0 context.Context, arg1 go_services.Service) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BeforeReload", arg0, arg1)
+}
+
+// BeforeReload indicates an expected call of BeforeReload.
+func (mr *MockReloadReporterMockRecorder) BeforeReload(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeforeReload", reflect.TypeOf((*MockReloadReporter)(nil).BeforeReload), arg0, arg1)
+}
+
+// BeforeStart mocks base method.
+func (m *MockReloadReporter) BeforeStart(arg0 context.Context, arg1 go_services.Service) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BeforeStart", arg0, arg1)
+}
+
+// BeforeStart indicates an expected call of BeforeStart.
+func (mr *MockReloadReporterMockRecorder) BeforeStart(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeforeStart", reflect.TypeOf((*MockReloadReporter)(nil).BeforeStart), arg0, arg1)
+}
+
+// BeforeStop mocks base method.
+func (m *MockReloadReporter) BeforeStop(arg0 context.Context, arg1 go_services.Service) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BeforeStop", arg0, arg1)
+}
+
+// BeforeStop indicates an expected call of BeforeStop.
+func (mr *MockReloadReporterMockRecorder) BeforeStop(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeforeStop", reflect.TypeOf((*MockReloadReporter)(nil).BeforeStop), arg0, arg1)
+}
+
+// SignalReceived mocks base method.
+func (m *MockReloadReporter) SignalReceived(arg0 os.Signal) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SignalReceived", arg0)
+}
+
+// SignalReceived indicates an expected call of SignalReceived.
+func (mr *MockReloadReporterMockRecorder) SignalReceived(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignalReceived", reflect.TypeOf((*MockReloadReporter)(nil).SignalReceived), arg0)
+}
+
+// MockRunReporter is a mock of RunReporter interface.
+type MockRunReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockRunReporterMockRecorder
+}
+
+// MockRunReporterMockRecorder is the mock recorder for MockRunReporter.
+type MockRunReporterMockRecorder struct {
+	mock *MockRunReporter
+}
+
+// NewMockRunReporter creates a new mock instance.
+func NewMockRunReporter(ctrl *gomock.Controller) *MockRunReporter {
+	mock := &MockRunReporter{ctrl: ctrl}
+	mock.recorder = &MockRunReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRunReporter) EXPECT() *MockRunReporterMockRecorder {
+	return m.recorder
+}
+
+// AfterFinish mocks base method.
+func (m *MockRunReporter) AfterFinish(arg0 context.Context, arg1 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AfterFinish", arg0, arg1)
+}
+
+// AfterFinish indicates an expected call of AfterFinish.
+func (mr *MockRunReporterMockRecorder) AfterFinish(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AfterFinish", reflect.TypeOf((*MockRunReporter)(nil).AfterFinish), arg0, arg1)
+}
+
+// AfterLoad mocks base method.
+func (m *MockRunReporter) AfterLoad(arg0 context.Context, arg1 go_services.Configurable, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AfterLoad", arg0, arg1, arg2)
+}
+
+// AfterLoad indicates an expected call of AfterLoad.
+func (mr *MockRunReporterMockRecorder) AfterLoad(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AfterLoad", reflect.TypeOf((*MockRunReporter)(nil).AfterLoad), arg0, arg1, arg2)
+}
+
+// AfterRun mocks base method.
+func (m *MockRunReporter) AfterRun(arg0 context.Context, arg1 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AfterRun", arg0, arg1)
+}
+
+// AfterRun indicates an expected call of AfterRun.
+func (mr *MockRunReporterMockRecorder) AfterRun(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AfterRun", reflect.TypeOf((*MockRunReporter)(nil).AfterRun), arg0, arg1)
+}
+
+// AfterStart mocks base method.
+func (m *MockRunReporter) AfterStart(arg0 context.Context, arg1 go_services.Service, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AfterStart", arg0, arg1, arg2)
+}
+
+// AfterStart indicates an expected call of AfterStart.
+func (mr *MockRunReporterMockRecorder) AfterStart(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AfterStart", reflect.TypeOf((*MockRunReporter)(nil).AfterStart), arg0, arg1, arg2)
+}
+
+// AfterStop mocks base method.
+func (m *MockRunReporter) AfterStop(arg0 context.Context, arg1 go_services.Service, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AfterStop", arg0, arg1, arg2)
+}
+
+// AfterStop indicates an expected call of AfterStop.
+func (mr *MockRunReporterMockRecorder) AfterStop(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AfterStop", reflect.TypeOf((*MockRunReporter)(nil).AfterStop), arg0, arg1, arg2)
+}
+
+// BeforeFinish mocks base method.
+func (m *MockRunReporter) BeforeFinish(arg0 context.Context) context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeforeFinish", arg0)
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// BeforeFinish indicates an expected call of BeforeFinish.
+func (mr *MockRunReporterMockRecorder) BeforeFinish(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeforeFinish", reflect.TypeOf((*MockRunReporter)(nil).BeforeFinish), arg0)
+}
+
+// BeforeLoad mocks base method.
+func (m *MockRunReporter) BeforeLoad(arg0 context.Context, arg1 go_services.Configurable) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BeforeLoad", arg0, arg1)
+}
+
+// BeforeLoad indicates an expected call of BeforeLoad.
+func (mr *MockRunReporterMockRecorder) BeforeLoad(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeforeLoad", reflect.TypeOf((*MockRunReporter)(nil).BeforeLoad), arg0, arg1)
+}
+
+// BeforeRun mocks base method.
+func (m *MockRunReporter) BeforeRun(arg0 context.Context, arg1 []go_services.Service) context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeforeRun", arg0, arg1)
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// BeforeRun indicates an expected call of BeforeRun.
+func (mr *MockRunReporterMockRecorder) BeforeRun(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeforeRun", reflect.TypeOf((*MockRunReporter)(nil).BeforeRun), arg0, arg1)
+}
+
+// BeforeStart mocks base method.
+func (m *MockRunReporter) BeforeStart(arg0 context.Context, arg1 go_services.Service) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BeforeStart", arg0, arg1)
+}
+
+// BeforeStart indicates an expected call of BeforeStart.
+func (mr *MockRunReporterMockRecorder) BeforeStart(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeforeStart", reflect.TypeOf((*MockRunReporter)(nil).BeforeStart), arg0, arg1)
+}
+
+// BeforeStop mocks base method.
+func (m *MockRunReporter) BeforeStop(arg0 context.Context, arg1 go_services.Service) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BeforeStop", arg0, arg1)
+}
+
+// BeforeStop indicates an expected call of BeforeStop.
+func (mr *MockRunReporterMockRecorder) BeforeStop(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeforeStop", reflect.TypeOf((*MockRunReporter)(nil).BeforeStop), arg0, arg1)
+}
+
+// SignalReceived mocks base method.
+func (m *MockRunReporter) SignalReceived(arg0 os.Signal) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SignalReceived", arg0)
+}
+
+// SignalReceived indicates an expected call of SignalReceived.
+func (mr *MockRunReporterMockRecorder) SignalReceived(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignalReceived", reflect.TypeOf((*MockRunReporter)(nil).SignalReceived), arg0)
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// NewMultiReporter creates a Reporter that forwards each event to all the given reporters, in order. The methods of
+// the optional reporter interfaces (e.g. RetrierReporter) are only forwarded to the reporters that implement them. The
+// ctx returned by the RunReporter.BeforeRun (or RunReporter.BeforeFinish) of a reporter is given to the next one. Nil
+// reporters are ignored.
+func NewMultiReporter(reporters ...Reporter) *EventReporter {
+	rs := make([]Reporter, 0, len(reporters))
+	for _, reporter := range reporters {
+		if reporter != nil {
+			rs = append(rs, reporter)
+		}
+	}
+	return &EventReporter{
+		handle: func(ctx context.Context, event Event) context.Context {
+			for _, reporter := range rs {
+				ctx = dispatch(ctx, reporter, event)
+			}
+			return ctx
+		},
+	}
+}
+
+// NewFilterReporter creates a Reporter that forwards to the given reporter only the events of the services with the
+// given names. The events that are not related to a service (e.g. EventSignalReceived and EventBeforeShutdownPhase)
+// are always forwarded.
+func NewFilterReporter(reporter Reporter, names ...string) *EventReporter {
+	accepted := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		accepted[name] = struct{}{}
+	}
+	return &EventReporter{
+		handle: func(ctx context.Context, event Event) context.Context {
+			if event.Service != nil {
+				if _, ok := accepted[event.Service.Name()]; !ok {
+					return ctx
+				}
+			}
+			return dispatch(ctx, reporter, event)
+		},
+	}
+}
+
+// NopReporter is a Reporter that ignores all events. It implements all the reporter interfaces, so a reporter that
+// handles only some events can embed it:
+//
+//	type failureReporter struct {
+//		services.NopReporter
+//	}
+//
+//	func (reporter *failureReporter) AfterStart(ctx context.Context, service services.Service, err error) {
+//		// ...
+//	}
+type NopReporter struct{}
+
+// BeforeStart does nothing.
+func (NopReporter) BeforeStart(context.Context, Service) {}
+
+// AfterStart does nothing.
+func (NopReporter) AfterStart(context.Context, Service, error) {}
+
+// BeforeStop does nothing.
+func (NopReporter) BeforeStop(context.Context, Service) {}
+
+// AfterStop does nothing.
+func (NopReporter) AfterStop(context.Context, Service, error) {}
+
+// BeforeLoad does nothing.
+func (NopReporter) BeforeLoad(context.Context, Configurable) {}
+
+// AfterLoad does nothing.
+func (NopReporter) AfterLoad(context.Context, Configurable, error) {}
+
+// SignalReceived does nothing.
+func (NopReporter) SignalReceived(os.Signal) {}
+
+// BeforeRetry does nothing.
+func (NopReporter) BeforeRetry(context.Context, Service, int) {}
+
+// AfterRetry does nothing.
+func (NopReporter) AfterRetry(context.Context, Service, int, error, time.Duration) {}
+
+// BeforeRestart does nothing.
+func (NopReporter) BeforeRestart(context.Context, Service, int, error) {}
+
+// BeforeShutdownPhase does nothing.
+func (NopReporter) BeforeShutdownPhase(context.Context, string) {}
+
+// AfterShutdownPhase does nothing.
+func (NopReporter) AfterShutdownPhase(context.Context, string, error) {}
+
+// PanicRecovered does nothing.
+func (NopReporter) PanicRecovered(context.Context, *PanicError) {}
+
+// BeforeReload does nothing.
+func (NopReporter) BeforeReload(context.Context, Service) {}
+
+// AfterReload does nothing.
+func (NopReporter) AfterReload(context.Context, Service, error) {}
+
+// BeforeRun returns the ctx as is.
+func (NopReporter) BeforeRun(ctx context.Context, _ []Service) context.Context { return ctx }
+
+// AfterRun does nothing.
+func (NopReporter) AfterRun(context.Context, error) {}
+
+// BeforeFinish returns the ctx as is.
+func (NopReporter) BeforeFinish(ctx context.Context) context.Context { return ctx }
+
+// AfterFinish does nothing.
+func (NopReporter) AfterFinish(context.Context, error) {}
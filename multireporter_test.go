@@ -0,0 +1,197 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"syscall"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/setare/go-services"
+)
+
+// failureReporter is a partial reporter that only keeps the services that failed to start.
+type failureReporter struct {
+	services.NopReporter
+	failed []services.Service
+}
+
+func (reporter *failureReporter) AfterStart(_ context.Context, service services.Service, err error) {
+	if err != nil {
+		reporter.failed = append(reporter.failed, service)
+	}
+}
+
+var _ = Describe("MultiReporter", func() {
+	var _ services.RunReporter = services.NewMultiReporter()
+	var _ services.RetrierReporter = services.NewMultiReporter()
+	var _ services.AttemptReporter = services.NewMultiReporter()
+	var _ services.SupervisorReporter = services.NewMultiReporter()
+	var _ services.PhaseReporter = services.NewMultiReporter()
+	var _ services.PanicReporter = services.NewMultiReporter()
+	var _ services.ReloadReporter = services.NewMultiReporter()
+
+	It("should notify all the reporters given to WithReporter, in order", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		serviceA := NewMockResource(ctrl)
+		wantErr := errors.New("start failed")
+
+		reporterA := NewMockReporter(ctrl)
+		reporterB := NewMockReporter(ctrl)
+		gomock.InOrder(
+			reporterA.EXPECT().BeforeStart(gomock.Any(), serviceA),
+			reporterB.EXPECT().BeforeStart(gomock.Any(), serviceA),
+			serviceA.EXPECT().Start(gomock.Any()).Return(wantErr),
+			reporterA.EXPECT().AfterStart(gomock.Any(), serviceA, wantErr),
+			reporterB.EXPECT().AfterStart(gomock.Any(), serviceA, wantErr),
+		)
+
+		runner := services.NewRunner(services.WithReporter(reporterA), services.WithReporter(reporterB))
+		Expect(runner.Run(ctx, serviceA)).To(MatchError(wantErr))
+	})
+
+	It("should forward the optional methods only to the reporters that implement them", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		type ctxKey struct{}
+		ctx := context.TODO()
+		hasValue := ctxWithValue{ctxKey{}}
+
+		serviceA := NewMockResource(ctrl)
+
+		reporter := NewMockReporter(ctrl)
+		runReporter := NewMockRunReporter(ctrl)
+		gomock.InOrder(
+			runReporter.EXPECT().BeforeRun(ctx, []services.Service{serviceA}).DoAndReturn(func(ctx context.Context, _ []services.Service) context.Context {
+				return context.WithValue(ctx, ctxKey{}, "run")
+			}),
+			reporter.EXPECT().BeforeStart(hasValue, serviceA),
+			runReporter.EXPECT().BeforeStart(hasValue, serviceA),
+			serviceA.EXPECT().Start(hasValue),
+			reporter.EXPECT().AfterStart(hasValue, serviceA, nil),
+			runReporter.EXPECT().AfterStart(hasValue, serviceA, nil),
+			runReporter.EXPECT().AfterRun(hasValue, nil),
+		)
+
+		runner := services.NewRunner(services.WithReporter(services.NewMultiReporter(reporter, nil, runReporter)))
+		Expect(runner.Run(ctx, serviceA)).To(Succeed())
+	})
+
+	It("should forward only the events of the selected services", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		serviceA := NewMockResource(ctrl)
+		serviceA.EXPECT().Name().Return("Service A").AnyTimes()
+		serviceA.EXPECT().Start(gomock.Any())
+		serviceB := NewMockResource(ctrl)
+		serviceB.EXPECT().Name().Return("Service B").AnyTimes()
+		serviceB.EXPECT().Start(gomock.Any())
+
+		reporter := NewMockRunReporter(ctrl)
+		gomock.InOrder(
+			reporter.EXPECT().BeforeRun(ctx, []services.Service{serviceA, serviceB}).Return(ctx),
+			reporter.EXPECT().BeforeStart(gomock.Any(), serviceB),
+			reporter.EXPECT().AfterStart(gomock.Any(), serviceB, nil),
+			reporter.EXPECT().AfterRun(gomock.Any(), nil),
+		)
+		reporter.EXPECT().SignalReceived(syscall.SIGTERM)
+
+		filter := services.NewFilterReporter(reporter, "Service B")
+		runner := services.NewRunner(services.WithReporter(filter))
+		Expect(runner.Run(ctx, serviceA, serviceB)).To(Succeed())
+		filter.SignalReceived(syscall.SIGTERM)
+	})
+
+	It("should let partial reporters embed the NopReporter", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		serviceA := NewMockResource(ctrl)
+		wantErr := errors.New("start failed")
+		serviceA.EXPECT().Start(gomock.Any()).Return(wantErr)
+		serviceA.EXPECT().Stop(gomock.Any()).Times(0)
+
+		reporter := &failureReporter{}
+		runner := services.NewRunner(services.WithReporter(reporter))
+		Expect(runner.Run(ctx, serviceA)).To(MatchError(wantErr))
+		Expect(reporter.failed).To(Equal([]services.Service{serviceA}))
+	})
+})
+
+var _ = Describe("EventReporter", func() {
+	It("should send all the events to the channel", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		serviceA := NewMockResource(ctrl)
+		serviceA.EXPECT().Start(gomock.Any())
+		serviceA.EXPECT().Stop(gomock.Any())
+
+		ch := make(chan services.Event, 10)
+		runner := services.NewRunner(services.WithReporter(services.NewChannelReporter(ch)))
+		Expect(runner.Run(ctx, serviceA)).To(Succeed())
+		Expect(runner.Finish(ctx)).To(Succeed())
+		close(ch)
+
+		kinds := make([]services.EventKind, 0)
+		for event := range ch {
+			Expect(event.Time).ToNot(BeZero())
+			kinds = append(kinds, event.Kind)
+			switch event.Kind {
+			case services.EventBeforeStart, services.EventAfterStart, services.EventBeforeStop, services.EventAfterStop:
+				Expect(event.Service).To(Equal(serviceA))
+			case services.EventBeforeRun:
+				Expect(event.Services).To(Equal([]services.Service{serviceA}))
+			}
+		}
+		Expect(kinds).To(Equal([]services.EventKind{
+			services.EventBeforeRun, services.EventBeforeStart, services.EventAfterStart, services.EventAfterRun,
+			services.EventBeforeFinish, services.EventBeforeStop, services.EventAfterStop, services.EventAfterFinish,
+		}))
+	})
+
+	It("should turn each callback into an event", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		serviceA := NewMockResource(ctrl)
+		wantErr := errors.New("attempt failed")
+
+		var events []services.Event
+		reporter := services.NewEventReporter(func(_ context.Context, event services.Event) {
+			events = append(events, event)
+		})
+		reporter.AfterRetry(context.TODO(), serviceA, 2, wantErr, backoff.Stop)
+		reporter.BeforeRestart(context.TODO(), serviceA, 3, wantErr)
+		reporter.AfterShutdownPhase(context.TODO(), "http", wantErr)
+		reporter.SignalReceived(syscall.SIGINT)
+
+		Expect(events).To(HaveLen(4))
+		Expect(events[0].Kind).To(Equal(services.EventAfterRetry))
+		Expect(events[0].Service).To(Equal(serviceA))
+		Expect(events[0].Attempt).To(Equal(2))
+		Expect(events[0].Err).To(Equal(wantErr))
+		Expect(events[0].Delay).To(Equal(backoff.Stop))
+		Expect(events[1].Kind).To(Equal(services.EventBeforeRestart))
+		Expect(events[1].Attempt).To(Equal(3))
+		Expect(events[2].Kind).To(Equal(services.EventAfterShutdownPhase))
+		Expect(events[2].Phase).To(Equal("http"))
+		Expect(events[3].Kind).To(Equal(services.EventSignalReceived))
+		Expect(events[3].Signal).To(Equal(syscall.SIGINT))
+	})
+})
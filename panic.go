@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is the error recorded when a service panics while it is loading, starting, listening, closing, stopping
+// or reloading. The panic is recovered, so the Runner proceeds with the graceful shutdown of the other services as if
+// the service had failed with this error. It matches ErrPanic when using `errors.Is` and, if the panic value is an
+// error, it also matches it.
+type PanicError struct {
+	Service Service
+	// Operation is the lifecycle call that panicked: "load", "start", "listen", "close", "stop" or "reload".
+	Operation string
+	// Value is the value given to panic.
+	Value interface{}
+	// Stack is the stack trace of the goroutine that panicked.
+	Stack []byte
+}
+
+func (err *PanicError) Error() string {
+	return fmt.Sprintf("%s: %s panicked on %s: %v", ErrPanic, serviceName(err.Service), err.Operation, err.Value)
+}
+
+// Is implements the `errors.Is` support.
+func (err *PanicError) Is(target error) bool {
+	return target == ErrPanic
+}
+
+// Unwrap returns the panic value, if it is an error.
+func (err *PanicError) Unwrap() error {
+	if e, ok := err.Value.(error); ok {
+		return e
+	}
+	return nil
+}
+
+// PanicReporter is a Reporter that is also notified when a panic of a service is recovered (check PanicError).
+type PanicReporter interface {
+	Reporter
+	PanicRecovered(context.Context, *PanicError)
+}
+
+// recoverPanic calls fn converting a panic into a PanicError, which is returned and reported to the reporter (if it
+// implements PanicReporter).
+func recoverPanic(ctx context.Context, reporter Reporter, service Service, operation string, fn func() error) (err error) {
+	defer func() {
+		value := recover()
+		if value == nil {
+			return
+		}
+		panicErr := &PanicError{
+			Service:   service,
+			Operation: operation,
+			Value:     value,
+			Stack:     debug.Stack(),
+		}
+		if panicReporter, ok := reporter.(PanicReporter); ok {
+			panicReporter.PanicRecovered(ctx, panicErr)
+		}
+		err = panicErr
+	}()
+	return fn()
+}
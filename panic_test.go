@@ -0,0 +1,143 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/setare/go-services"
+)
+
+var _ = Describe("Panic recovery", func() {
+	It("should recover a panic in Resource.Start", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		serviceA := NewMockResource(ctrl)
+		serviceA.EXPECT().Name().Return("Service A").AnyTimes()
+		serviceA.EXPECT().Start(gomock.Any()).Do(func(context.Context) {
+			panic("random panic")
+		})
+
+		reporter := NewMockPanicReporter(ctrl)
+		reporter.EXPECT().BeforeStart(gomock.Any(), serviceA)
+		reporter.EXPECT().AfterStart(gomock.Any(), serviceA, gomock.Any())
+		reporter.EXPECT().PanicRecovered(gomock.Any(), gomock.Any()).Do(func(_ context.Context, err *services.PanicError) {
+			defer GinkgoRecover()
+			Expect(err.Service).To(Equal(serviceA))
+		})
+
+		runner := services.NewRunner(services.WithReporter(reporter))
+		err := runner.Run(ctx, serviceA)
+		Expect(err).To(MatchError(services.ErrPanic))
+		Expect(err.Error()).To(Equal("panic recovered: Service A panicked on start: random panic"))
+
+		var panicErr *services.PanicError
+		Expect(errors.As(err, &panicErr)).To(BeTrue())
+		Expect(panicErr.Service).To(Equal(serviceA))
+		Expect(panicErr.Operation).To(Equal("start"))
+		Expect(panicErr.Value).To(Equal("random panic"))
+		Expect(string(panicErr.Stack)).To(ContainSubstring("panic_test.go"))
+
+		statuses := runner.Services()
+		Expect(statuses).To(HaveLen(1))
+		Expect(statuses[0].State).To(Equal(services.StateFailed))
+	})
+
+	It("should recover a panic in Configurable.Load matching the error panicked", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		wantErr := errors.New("random error")
+		serviceA := &struct {
+			*MockResource
+			*MockConfigurable
+		}{
+			MockResource:     NewMockResource(ctrl),
+			MockConfigurable: NewMockConfigurable(ctrl),
+		}
+		serviceA.MockResource.EXPECT().Name().Return("Service A").AnyTimes()
+		serviceA.MockConfigurable.EXPECT().Load(gomock.Any()).Do(func(context.Context) {
+			panic(wantErr)
+		})
+
+		runner := services.NewRunner()
+		err := runner.Run(ctx, serviceA)
+		Expect(err).To(MatchError(services.ErrPanic))
+		Expect(err).To(MatchError(wantErr))
+	})
+
+	It("should close the other servers when a Server.Listen panics", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		closed := make(chan struct{})
+		serverA := NewMockServer(ctrl)
+		serverA.EXPECT().Name().Return("Server A").AnyTimes()
+		serverA.EXPECT().Listen(gomock.Any()).Do(func(context.Context) {
+			<-closed
+		})
+		serverA.EXPECT().Close(gomock.Any()).Do(func(context.Context) {
+			close(closed)
+		})
+
+		serverB := NewMockServer(ctrl)
+		serverB.EXPECT().Name().Return("Server B").AnyTimes()
+		serverB.EXPECT().Listen(gomock.Any()).Do(func(context.Context) {
+			panic("random panic")
+		})
+		serverB.EXPECT().Close(gomock.Any())
+
+		runner := services.NewRunner()
+		err := runner.Run(ctx, serverA, serverB)
+		Expect(err).To(MatchError(services.ErrPanic))
+
+		var panicErr *services.PanicError
+		Expect(errors.As(err, &panicErr)).To(BeTrue())
+		Expect(panicErr.Service).To(Equal(serverB))
+		Expect(panicErr.Operation).To(Equal("listen"))
+
+		state, _ := runner.State("Server B")
+		Expect(state).To(Equal(services.StateFailed))
+	})
+
+	It("should stop the other resources when a Resource.Stop panics", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		serviceA := NewMockResource(ctrl)
+		serviceA.EXPECT().Name().Return("Service A").AnyTimes()
+		serviceB := NewMockResource(ctrl)
+		serviceB.EXPECT().Name().Return("Service B").AnyTimes()
+
+		gomock.InOrder(
+			serviceA.EXPECT().Start(gomock.Any()),
+			serviceB.EXPECT().Start(gomock.Any()),
+			serviceB.EXPECT().Stop(gomock.Any()).Do(func(context.Context) {
+				panic("random panic")
+			}),
+			serviceA.EXPECT().Stop(gomock.Any()),
+		)
+
+		runner := services.NewRunner()
+		Expect(runner.Run(ctx, serviceA, serviceB)).To(Succeed())
+
+		err := runner.Finish(ctx)
+		Expect(err).To(MatchError(services.ErrPanic))
+
+		var stopErr *services.StopError
+		Expect(errors.As(err, &stopErr)).To(BeTrue())
+		Expect(stopErr.Service).To(Equal(serviceB))
+	})
+})
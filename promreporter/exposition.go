@@ -0,0 +1,175 @@
+package promreporter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the content type of the Prometheus text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+type histogram struct {
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+}
+
+func newHistogram(buckets []float64) *histogram {
+	return &histogram{
+		buckets: buckets,
+		counts:  make([]uint64, len(buckets)),
+	}
+}
+
+func (h *histogram) observe(value float64) {
+	for idx, bound := range h.buckets {
+		if value <= bound {
+			h.counts[idx]++
+		}
+	}
+	h.sum += value
+	h.count++
+}
+
+// expositionWriter writes metrics using the Prometheus text exposition format, keeping the first error.
+type expositionWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (ew *expositionWriter) printf(format string, args ...interface{}) {
+	if ew.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(ew.w, format, args...)
+	ew.n += int64(n)
+	ew.err = err
+}
+
+func (ew *expositionWriter) header(name, help, typ string) {
+	ew.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+func (ew *expositionWriter) sample(name, labels string, value float64) {
+	ew.printf("%s{%s} %s\n", name, labels, formatFloat(value))
+}
+
+func (ew *expositionWriter) counter(name, help string, values map[string]float64, label string) {
+	ew.header(name, help, "counter")
+	for _, key := range sortedKeys(values) {
+		ew.sample(name, labelPairs(label, key), values[key])
+	}
+}
+
+// operationCounter writes a counter labeled by service and operation.
+func (ew *expositionWriter) operationCounter(name, help string, values map[[2]string]float64) {
+	ew.header(name, help, "counter")
+	keys := make([][2]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] == keys[j][0] {
+			return keys[i][1] < keys[j][1]
+		}
+		return keys[i][0] < keys[j][0]
+	})
+	for _, key := range keys {
+		ew.sample(name, labelPairs("service", key[0])+","+labelPairs("operation", key[1]), values[key])
+	}
+}
+
+func (ew *expositionWriter) histogram(name, help string, histograms map[string]*histogram) {
+	ew.header(name, help, "histogram")
+	names := make([]string, 0, len(histograms))
+	for service := range histograms {
+		names = append(names, service)
+	}
+	sort.Strings(names)
+	for _, service := range names {
+		h := histograms[service]
+		serviceLabel := labelPairs("service", service)
+		for idx, bound := range h.buckets {
+			ew.sample(name+"_bucket", serviceLabel+","+labelPairs("le", formatFloat(bound)), float64(h.counts[idx]))
+		}
+		ew.sample(name+"_bucket", serviceLabel+","+labelPairs("le", "+Inf"), float64(h.count))
+		ew.sample(name+"_sum", serviceLabel, h.sum)
+		ew.sample(name+"_count", serviceLabel, float64(h.count))
+	}
+}
+
+// WriteTo writes the collected metrics to w using the Prometheus text exposition format.
+func (reporter *Reporter) WriteTo(w io.Writer) (int64, error) {
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+
+	ew := &expositionWriter{w: bufio.NewWriter(w)}
+	prefix := reporter.namespace
+	if prefix != "" {
+		prefix += "_"
+	}
+
+	ew.histogram(prefix+"start_duration_seconds", "How long the services took to start.", reporter.starts)
+	ew.histogram(prefix+"stop_duration_seconds", "How long the services took to stop.", reporter.stops)
+
+	ew.operationCounter(prefix+"failures_total", "How many times the services failed, by operation.", reporter.failures)
+	ew.operationCounter(prefix+"panics_total", "How many panics of the services were recovered, by operation.", reporter.panics)
+
+	ew.counter(prefix+"reloads_total", "How many times the services were reloaded.", reporter.reloads, "service")
+	ew.counter(prefix+"retries_total", "How many times starting the services was retried.", reporter.retries, "service")
+	ew.counter(prefix+"restarts_total", "How many times the supervised servers were restarted.", reporter.restarts, "service")
+	ew.counter(prefix+"signals_received_total", "How many signals were received.", reporter.signals, "signal")
+
+	ew.header(prefix+"state", "The current state of the services.", "gauge")
+	names := make([]string, 0, len(reporter.states))
+	for service := range reporter.states {
+		names = append(names, service)
+	}
+	sort.Strings(names)
+	for _, service := range names {
+		for _, state := range States {
+			value := 0.0
+			if reporter.states[service] == state {
+				value = 1
+			}
+			ew.sample(prefix+"state", labelPairs("service", service)+","+labelPairs("state", state), value)
+		}
+	}
+
+	if ew.err != nil {
+		return ew.n, ew.err
+	}
+	return ew.n, ew.w.Flush()
+}
+
+func sortedKeys(values map[string]float64) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func labelPairs(name, value string) string {
+	return name + `="` + labelValueReplacer.Replace(value) + `"`
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,270 @@
+// Package promreporter implements a services.Reporter that collects metrics of the lifecycle of the services and
+// exposes them using the Prometheus text exposition format.
+package promreporter
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/setare/go-services"
+)
+
+// The states of a service exposed by the state gauge.
+const (
+	StateStarting = "starting"
+	StateRunning  = "running"
+	StateStopping = "stopping"
+	StateStopped  = "stopped"
+	StateFailed   = "failed"
+)
+
+// States is the list of states exposed by the state gauge, in the order they are exposed.
+var States = []string{StateStarting, StateRunning, StateStopping, StateStopped, StateFailed}
+
+// DefaultBuckets are the default buckets of the duration histograms, in seconds. They are the same used by the
+// Prometheus client libraries.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+type durationKey struct {
+	action string
+	// service is the services.ServiceKey of the service.
+	service interface{}
+}
+
+// Reporter collects the metrics of the lifecycle events reported by a services.Runner, a
+// services.ResourceServiceRetrier or a services.ServerSupervisor. It is also an `http.Handler` that serves the
+// collected metrics using the Prometheus text exposition format.
+//
+// The following metrics are collected (the prefix can be changed by WithNamespace):
+//
+//   - services_start_duration_seconds: histogram of how long each Resource took to start;
+//   - services_stop_duration_seconds: histogram of how long each Resource took to stop, or each Server took to close;
+//   - services_failures_total: counter of failures by service and operation (load, start, stop, reload or attempt);
+//   - services_reloads_total: counter of the successful reloads of a service;
+//   - services_panics_total: counter of the panics recovered by service and operation (check services.PanicError);
+//   - services_retries_total: counter of the retries of starting a service;
+//   - services_restarts_total: counter of the restarts of a supervised server;
+//   - services_signals_received_total: counter of the signals received by the Runner;
+//   - services_state: gauge that is 1 for the current state of each service and 0 for the others (check States).
+//
+// Since a Server listens during its whole lifetime, it goes straight to the running state when it is started. Servers
+// that implement services.ReadyNotifier are starting until they are ready, and their start duration is observed.
+type Reporter struct {
+	namespace string
+	buckets   []float64
+
+	mutex     sync.Mutex
+	startedAt map[durationKey]time.Time
+	starts    map[string]*histogram
+	stops     map[string]*histogram
+	failures  map[[2]string]float64
+	panics    map[[2]string]float64
+	reloads   map[string]float64
+	retries   map[string]float64
+	restarts  map[string]float64
+	signals   map[string]float64
+	states    map[string]string
+}
+
+// Option configures a Reporter created by New.
+type Option = func(*Reporter)
+
+// WithNamespace is an Option that sets the prefix of the metric names. The default is "services".
+func WithNamespace(namespace string) Option {
+	return func(reporter *Reporter) {
+		reporter.namespace = namespace
+	}
+}
+
+// WithBuckets is an Option that sets the buckets, in seconds, of the duration histograms. The default is
+// DefaultBuckets.
+func WithBuckets(buckets ...float64) Option {
+	return func(reporter *Reporter) {
+		reporter.buckets = buckets
+	}
+}
+
+// New creates a new Reporter.
+func New(opts ...Option) *Reporter {
+	reporter := &Reporter{
+		namespace: "services",
+		buckets:   DefaultBuckets,
+		startedAt: make(map[durationKey]time.Time),
+		starts:    make(map[string]*histogram),
+		stops:     make(map[string]*histogram),
+		failures:  make(map[[2]string]float64),
+		panics:    make(map[[2]string]float64),
+		reloads:   make(map[string]float64),
+		retries:   make(map[string]float64),
+		restarts:  make(map[string]float64),
+		signals:   make(map[string]float64),
+		states:    make(map[string]string),
+	}
+	for _, opt := range opts {
+		opt(reporter)
+	}
+	return reporter
+}
+
+// BeforeStart sets the service as starting. A Server is set as running, unless it implements services.ReadyNotifier.
+func (reporter *Reporter) BeforeStart(_ context.Context, service services.Service) {
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+
+	if _, ok := service.(services.Server); ok {
+		if _, ok := services.As[services.ReadyNotifier](service); !ok {
+			reporter.states[service.Name()] = StateRunning
+			return
+		}
+	}
+	reporter.startedAt[durationKey{"start", services.ServiceKey(service)}] = time.Now()
+	reporter.states[service.Name()] = StateStarting
+}
+
+// AfterStart observes the start duration of the service and sets it as running, or as failed.
+func (reporter *Reporter) AfterStart(_ context.Context, service services.Service, err error) {
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+
+	reporter.observe(reporter.starts, "start", service)
+	reporter.setState(service, "start", err, StateRunning)
+}
+
+// BeforeStop sets the service as stopping.
+func (reporter *Reporter) BeforeStop(_ context.Context, service services.Service) {
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+
+	reporter.startedAt[durationKey{"stop", services.ServiceKey(service)}] = time.Now()
+	reporter.states[service.Name()] = StateStopping
+}
+
+// AfterStop observes the stop duration of the service and sets it as stopped, or as failed.
+func (reporter *Reporter) AfterStop(_ context.Context, service services.Service, err error) {
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+
+	reporter.observe(reporter.stops, "stop", service)
+	reporter.setState(service, "stop", err, StateStopped)
+}
+
+// BeforeLoad does nothing.
+func (reporter *Reporter) BeforeLoad(context.Context, services.Configurable) {}
+
+// AfterLoad counts the failure of loading the configuration of the service, if any.
+func (reporter *Reporter) AfterLoad(_ context.Context, configurable services.Configurable, err error) {
+	if err == nil {
+		return
+	}
+	service, ok := configurable.(services.Service)
+	if !ok {
+		return
+	}
+
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+
+	reporter.failures[[2]string{service.Name(), "load"}]++
+	reporter.states[service.Name()] = StateFailed
+}
+
+// BeforeReload does nothing, reloads are only counted when they finish.
+func (reporter *Reporter) BeforeReload(context.Context, services.Service) {}
+
+// AfterReload counts the successful reloads and the failures of reloading the service. The state is not changed, since
+// a service that fails to reload keeps running.
+func (reporter *Reporter) AfterReload(_ context.Context, service services.Service, err error) {
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+
+	if err != nil {
+		reporter.failures[[2]string{service.Name(), "reload"}]++
+		return
+	}
+	reporter.reloads[service.Name()]++
+}
+
+// SignalReceived counts the signal received.
+func (reporter *Reporter) SignalReceived(sig os.Signal) {
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+
+	reporter.signals[sig.String()]++
+}
+
+// BeforeRetry counts the retries of starting the service. The first attempt is not counted.
+func (reporter *Reporter) BeforeRetry(_ context.Context, service services.Service, attempt int) {
+	if attempt <= 1 {
+		return
+	}
+
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+
+	reporter.retries[service.Name()]++
+}
+
+// AfterRetry counts the failed attempts of starting the service.
+func (reporter *Reporter) AfterRetry(_ context.Context, service services.Service, _ int, err error, _ time.Duration) {
+	if err == nil {
+		return
+	}
+
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+
+	reporter.failures[[2]string{service.Name(), "attempt"}]++
+}
+
+// BeforeRestart counts the restarts of a supervised server.
+func (reporter *Reporter) BeforeRestart(_ context.Context, service services.Service, _ int, _ error) {
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+
+	reporter.restarts[service.Name()]++
+}
+
+// PanicRecovered counts the panics recovered. The failure itself is counted by the After event of the operation.
+func (reporter *Reporter) PanicRecovered(_ context.Context, err *services.PanicError) {
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+
+	reporter.panics[[2]string{err.Service.Name(), err.Operation}]++
+}
+
+// ServeHTTP writes the collected metrics using the Prometheus text exposition format.
+func (reporter *Reporter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	_, _ = reporter.WriteTo(w)
+}
+
+// observe adds the duration since the Before event of the given action to the histogram of the service. It must be
+// called holding the mutex.
+func (reporter *Reporter) observe(histograms map[string]*histogram, action string, service services.Service) {
+	key := durationKey{action, services.ServiceKey(service)}
+	startedAt, ok := reporter.startedAt[key]
+	if !ok {
+		return
+	}
+	delete(reporter.startedAt, key)
+
+	h, ok := histograms[service.Name()]
+	if !ok {
+		h = newHistogram(reporter.buckets)
+		histograms[service.Name()] = h
+	}
+	h.observe(time.Since(startedAt).Seconds())
+}
+
+// setState sets the state of the service given the result of the operation, counting the failure, if any. It must be
+// called holding the mutex.
+func (reporter *Reporter) setState(service services.Service, operation string, err error, state string) {
+	if err != nil {
+		reporter.failures[[2]string{service.Name(), operation}]++
+		state = StateFailed
+	}
+	reporter.states[service.Name()] = state
+}
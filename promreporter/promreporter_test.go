@@ -0,0 +1,218 @@
+package promreporter_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/setare/go-services"
+	"github.com/setare/go-services/promreporter"
+	"github.com/setare/go-services/servicestest"
+)
+
+type resource struct {
+	name     string
+	startErr []error
+}
+
+func (r *resource) Name() string { return r.name }
+
+func (r *resource) Start(context.Context) error {
+	time.Sleep(time.Millisecond * 20)
+	if len(r.startErr) > 0 {
+		err := r.startErr[0]
+		r.startErr = r.startErr[1:]
+		return err
+	}
+	return nil
+}
+
+func (r *resource) Stop(context.Context) error { return nil }
+
+// taggedResource is a resource whose type is not comparable, since it holds a slice.
+type taggedResource struct {
+	name string
+	tags []string
+}
+
+func (r taggedResource) Name() string                { return r.name }
+func (r taggedResource) Start(context.Context) error { return nil }
+func (r taggedResource) Stop(context.Context) error  { return nil }
+
+type server struct {
+	name string
+}
+
+func (s *server) Name() string                 { return s.name }
+func (s *server) Listen(context.Context) error { return nil }
+func (s *server) Close(context.Context) error  { return nil }
+
+type readyServer struct {
+	server
+}
+
+func (s *readyServer) Ready() <-chan struct{} { return nil }
+
+// scrape gets the metrics served by the handler.
+func scrape(handler http.Handler) string {
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
+	Expect(resp.StatusCode).To(Equal(http.StatusOK))
+	Expect(resp.Header.Get("Content-Type")).To(Equal(promreporter.ContentType))
+
+	body, err := io.ReadAll(resp.Body)
+	Expect(err).ToNot(HaveOccurred())
+	return string(body)
+}
+
+var _ = Describe("Reporter", func() {
+	var _ services.RetrierReporter = &promreporter.Reporter{}
+	var _ services.AttemptReporter = &promreporter.Reporter{}
+	var _ services.SupervisorReporter = &promreporter.Reporter{}
+	var _ services.ReloadReporter = &promreporter.Reporter{}
+	var _ services.PanicReporter = &promreporter.Reporter{}
+	var _ http.Handler = &promreporter.Reporter{}
+
+	It("should expose the metrics of a Runner", func() {
+		ctx := context.TODO()
+		reporter := promreporter.New(promreporter.WithBuckets(0.01, 1))
+
+		serviceA := &resource{name: "Service A"}
+		retrier := services.Retrier().
+			Backoff(backoff.NewConstantBackOff(time.Millisecond)).
+			Reporter(reporter).
+			Build(&resource{name: "Service B", startErr: []error{errors.New("random error")}})
+
+		runner := services.NewRunner(services.WithReporter(reporter))
+		Expect(runner.Run(ctx, serviceA, retrier)).To(Succeed())
+
+		metrics := scrape(reporter)
+		Expect(metrics).To(ContainSubstring("# TYPE services_start_duration_seconds histogram\n"))
+		Expect(metrics).To(ContainSubstring(`services_start_duration_seconds_bucket{service="Service A",le="0.01"} 0` + "\n"))
+		Expect(metrics).To(ContainSubstring(`services_start_duration_seconds_bucket{service="Service A",le="1"} 1` + "\n"))
+		Expect(metrics).To(ContainSubstring(`services_start_duration_seconds_bucket{service="Service A",le="+Inf"} 1` + "\n"))
+		Expect(metrics).To(ContainSubstring(`services_start_duration_seconds_count{service="Service A"} 1` + "\n"))
+		Expect(metrics).To(ContainSubstring(`services_retries_total{service="Service B"} 1` + "\n"))
+		Expect(metrics).To(ContainSubstring(`services_state{service="Service A",state="running"} 1` + "\n"))
+		Expect(metrics).To(ContainSubstring(`services_state{service="Service A",state="stopped"} 0` + "\n"))
+
+		Expect(runner.Finish(ctx)).To(Succeed())
+
+		metrics = scrape(reporter)
+		Expect(metrics).To(ContainSubstring(`services_stop_duration_seconds_count{service="Service A"} 1` + "\n"))
+		Expect(metrics).To(ContainSubstring(`services_state{service="Service A",state="running"} 0` + "\n"))
+		Expect(metrics).To(ContainSubstring(`services_state{service="Service A",state="stopped"} 1` + "\n"))
+	})
+
+	It("should observe the services whose types are not comparable", func() {
+		ctx := context.TODO()
+		reporter := promreporter.New()
+
+		serviceA := taggedResource{name: "Service A", tags: []string{"db"}}
+		reporter.BeforeStart(ctx, serviceA)
+		reporter.AfterStart(ctx, serviceA, nil)
+
+		Expect(scrape(reporter)).To(ContainSubstring(`services_start_duration_seconds_count{service="Service A"} 1` + "\n"))
+	})
+
+	It("should count the failures", func() {
+		ctx := context.TODO()
+		reporter := promreporter.New(promreporter.WithNamespace("app"))
+
+		serviceA := &resource{name: "Service A", startErr: []error{errors.New("random error")}}
+
+		runner := services.NewRunner(services.WithReporter(reporter))
+		Expect(runner.Run(ctx, serviceA)).ToNot(Succeed())
+
+		metrics := scrape(reporter)
+		Expect(metrics).To(ContainSubstring(`app_failures_total{service="Service A",operation="start"} 1` + "\n"))
+		Expect(metrics).To(ContainSubstring(`app_state{service="Service A",state="failed"} 1` + "\n"))
+	})
+
+	It("should count the reloads", func() {
+		ctx := context.TODO()
+		reporter := promreporter.New()
+		serviceA := &resource{name: "Service A"}
+
+		reporter.BeforeStart(ctx, serviceA)
+		reporter.AfterStart(ctx, serviceA, nil)
+		reporter.BeforeReload(ctx, serviceA)
+		reporter.AfterReload(ctx, serviceA, nil)
+		reporter.BeforeReload(ctx, serviceA)
+		reporter.AfterReload(ctx, serviceA, errors.New("invalid configuration"))
+
+		metrics := scrape(reporter)
+		Expect(metrics).To(ContainSubstring(`services_reloads_total{service="Service A"} 1` + "\n"))
+		Expect(metrics).To(ContainSubstring(`services_failures_total{service="Service A",operation="reload"} 1` + "\n"))
+		Expect(metrics).To(ContainSubstring(`services_state{service="Service A",state="running"} 1` + "\n"))
+	})
+
+	It("should observe the start of a Server that notifies its readiness", func() {
+		ctx := context.TODO()
+		reporter := promreporter.New()
+		serverA := &readyServer{server{name: "Server A"}}
+
+		reporter.BeforeStart(ctx, serverA)
+		Expect(scrape(reporter)).To(ContainSubstring(`services_state{service="Server A",state="starting"} 1` + "\n"))
+
+		reporter.AfterStart(ctx, serverA, nil)
+		metrics := scrape(reporter)
+		Expect(metrics).To(ContainSubstring(`services_state{service="Server A",state="running"} 1` + "\n"))
+		Expect(metrics).To(ContainSubstring(`services_start_duration_seconds_count{service="Server A"} 1` + "\n"))
+	})
+
+	It("should observe the close of a Server", func() {
+		ctx, cancelFunc := context.WithCancel(context.TODO())
+		defer cancelFunc()
+
+		reporter := promreporter.New()
+		serverA := servicestest.NewServer("Server A")
+
+		runner := services.NewRunner(services.WithReporter(reporter))
+		ran := make(chan error)
+		go func() {
+			ran <- runner.Run(ctx, serverA)
+		}()
+		Eventually(serverA.Ready()).Should(BeClosed())
+		cancelFunc()
+		Eventually(ran).Should(Receive(MatchError(context.Canceled)))
+
+		metrics := scrape(reporter)
+		Expect(metrics).To(ContainSubstring(`services_stop_duration_seconds_count{service="Server A"} 1` + "\n"))
+		Expect(metrics).To(ContainSubstring(`services_state{service="Server A",state="stopped"} 1` + "\n"))
+	})
+
+	It("should count the restarts, signals and panics", func() {
+		ctx := context.TODO()
+		reporter := promreporter.New()
+		serverA := &server{name: "Server \"A\""}
+
+		reporter.BeforeStart(ctx, serverA)
+		reporter.BeforeRestart(ctx, serverA, 1, errors.New("connection reset"))
+		reporter.BeforeRestart(ctx, serverA, 2, errors.New("connection reset"))
+		reporter.SignalReceived(os.Interrupt)
+		reporter.PanicRecovered(ctx, &services.PanicError{Service: serverA, Operation: "listen", Value: "random panic"})
+
+		var sb strings.Builder
+		_, err := reporter.WriteTo(&sb)
+		Expect(err).ToNot(HaveOccurred())
+		metrics := sb.String()
+		Expect(metrics).To(ContainSubstring(`services_restarts_total{service="Server \"A\""} 2` + "\n"))
+		Expect(metrics).To(ContainSubstring(`services_signals_received_total{signal="interrupt"} 1` + "\n"))
+		Expect(metrics).To(ContainSubstring(`services_panics_total{service="Server \"A\"",operation="listen"} 1` + "\n"))
+		Expect(metrics).To(ContainSubstring(`services_state{service="Server \"A\"",state="running"} 1` + "\n"))
+	})
+})
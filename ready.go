@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// StartupTimeoutError is the error returned when a Server does not become ready within the startup timeout (check
+// WithStartupTimeout). It matches ErrStartupTimeout when using `errors.Is`.
+type StartupTimeoutError struct {
+	Service Service
+	Timeout time.Duration
+}
+
+func (err *StartupTimeoutError) Error() string {
+	return fmt.Sprintf("%s: %s was not ready within %s", ErrStartupTimeout, serviceName(err.Service), err.Timeout)
+}
+
+// Is implements the `errors.Is` support.
+func (err *StartupTimeoutError) Is(target error) bool {
+	return target == ErrStartupTimeout
+}
+
+// waitReady waits the given server to become ready. The done must be closed when the Listen of the server returns,
+// after that listenErr returns its error. It fails when the server exits before being ready, when the startup timeout
+// is exceeded or when the ctx is cancelled.
+func (r *Runner) waitReady(ctx context.Context, server Server, notifier ReadyNotifier, done <-chan struct{}, listenErr func() error) error {
+	var timeout <-chan time.Time
+	if r.startupTimeout > 0 {
+		timer := time.NewTimer(r.startupTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-notifier.Ready():
+		return nil
+	case <-done:
+		select {
+		case <-notifier.Ready():
+			// It became ready before exiting, its Listen error is handled as the error of a running server.
+			return nil
+		default:
+		}
+		if err := listenErr(); err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrExitedBeforeReady, serviceName(server), err)
+		}
+		return fmt.Errorf("%w: %s", ErrExitedBeforeReady, serviceName(server))
+	case <-timeout:
+		return &StartupTimeoutError{
+			Service: server,
+			Timeout: r.startupTimeout,
+		}
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
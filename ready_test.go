@@ -0,0 +1,189 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	signals "github.com/jamillosantos/go-os-signals"
+	"github.com/jamillosantos/go-os-signals/signaltest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/setare/go-services"
+)
+
+// readyServer is a Server that notifies its readiness through the ready channel.
+type readyServer struct {
+	*MockServer
+	ready chan struct{}
+}
+
+func newReadyServer(ctrl *gomock.Controller, name string) *readyServer {
+	server := &readyServer{
+		MockServer: NewMockServer(ctrl),
+		ready:      make(chan struct{}),
+	}
+	server.EXPECT().Name().Return(name).AnyTimes()
+	return server
+}
+
+func (server *readyServer) Ready() <-chan struct{} {
+	return server.ready
+}
+
+var _ = Describe("Readiness", func() {
+	It("should wait the Server to be ready before starting the next services", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx, cancelFunc := context.WithCancel(context.TODO())
+		defer cancelFunc()
+
+		closed := make(chan struct{})
+		serverA := newReadyServer(ctrl, "Server A")
+		serviceB := NewMockResource(ctrl)
+		serviceB.EXPECT().Name().Return("Service B").AnyTimes()
+
+		runner := services.NewRunner()
+
+		reporter := NewMockReporter(ctrl)
+		gomock.InOrder(
+			reporter.EXPECT().BeforeStart(gomock.Any(), serverA),
+			serverA.EXPECT().Listen(gomock.Any()).Do(func(context.Context) {
+				defer GinkgoRecover()
+				time.Sleep(time.Millisecond * 50)
+				Expect(currentState(runner, "Server A")).To(Equal(services.StateStarting))
+				close(serverA.ready)
+				<-closed
+			}),
+		)
+		gomock.InOrder(
+			reporter.EXPECT().AfterStart(gomock.Any(), serverA, nil),
+			reporter.EXPECT().BeforeStart(gomock.Any(), serviceB),
+			serviceB.EXPECT().Start(gomock.Any()).Do(func(context.Context) {
+				defer GinkgoRecover()
+				Expect(currentState(runner, "Server A")).To(Equal(services.StateRunning))
+				cancelFunc()
+			}),
+			reporter.EXPECT().AfterStart(gomock.Any(), serviceB, nil),
+		)
+		serverA.EXPECT().Close(gomock.Any()).Do(func(context.Context) {
+			close(closed)
+		})
+		reporter.EXPECT().BeforeStop(gomock.Any(), serverA)
+		reporter.EXPECT().AfterStop(gomock.Any(), serverA, nil)
+
+		runner.WithReporter(reporter)
+		Expect(runner.Run(ctx, serverA, serviceB)).To(MatchError(context.Canceled))
+	})
+
+	It("should fail when the Server exits before being ready", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		wantErr := errors.New("address already in use")
+		serverA := newReadyServer(ctrl, "Server A")
+		serverA.EXPECT().Listen(gomock.Any()).Return(wantErr)
+		serverA.EXPECT().Close(gomock.Any())
+		serviceB := NewMockResource(ctrl)
+
+		reporter := NewMockReporter(ctrl)
+		reporter.EXPECT().BeforeStart(gomock.Any(), serverA)
+		reporter.EXPECT().AfterStart(gomock.Any(), serverA, gomock.Any()).Do(func(_ context.Context, _ services.Service, err error) {
+			defer GinkgoRecover()
+			Expect(err).To(MatchError(services.ErrExitedBeforeReady))
+		})
+		reporter.EXPECT().BeforeStop(gomock.Any(), serverA)
+		reporter.EXPECT().AfterStop(gomock.Any(), serverA, nil)
+
+		runner := services.NewRunner(services.WithReporter(reporter))
+		err := runner.Run(ctx, serverA, serviceB)
+		Expect(err).To(MatchError(services.ErrExitedBeforeReady))
+		Expect(err).To(MatchError(wantErr))
+		Expect(err.Error()).To(Equal("server exited before ready: Server A: address already in use"))
+		Expect(currentState(runner, "Server A")).To(Equal(services.StateFailed))
+	})
+
+	It("should fail when the Server is not ready within the startup timeout", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		closed := make(chan struct{})
+		serverA := newReadyServer(ctrl, "Server A")
+		serverA.EXPECT().Listen(gomock.Any()).Do(func(context.Context) {
+			<-closed
+		})
+		serverA.EXPECT().Close(gomock.Any()).Do(func(context.Context) {
+			close(closed)
+		})
+
+		runner := services.NewRunner(services.WithStartupTimeout(time.Millisecond * 50))
+		err := runner.Run(ctx, serverA)
+		Expect(err).To(MatchError(services.ErrStartupTimeout))
+		Expect(err.Error()).To(Equal("startup timeout: Server A was not ready within 50ms"))
+
+		var timeoutErr *services.StartupTimeoutError
+		Expect(errors.As(err, &timeoutErr)).To(BeTrue())
+		Expect(timeoutErr.Service).To(Equal(serverA))
+		Expect(currentState(runner, "Server A")).To(Equal(services.StateFailed))
+	})
+
+	It("should cancel the Listen of a Server that is not listening yet when the startup timeout is exceeded", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		serverA := newReadyServer(ctrl, "Server A")
+		// The server is still setting up, so Close is a no-op and only the ctx interrupts the Listen.
+		serverA.EXPECT().Listen(gomock.Any()).DoAndReturn(func(ctx context.Context) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Second * 5):
+				return errors.New("the Listen was not cancelled")
+			}
+		})
+		serverA.EXPECT().Close(gomock.Any())
+
+		runner := services.NewRunner(services.WithStartupTimeout(time.Millisecond * 50))
+		started := time.Now()
+		Expect(runner.Run(ctx, serverA)).To(MatchError(services.ErrStartupTimeout))
+		Expect(time.Since(started)).To(BeNumerically("<", time.Second))
+	})
+
+	It("should cancel waiting the Server when a signal is received", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		listener := signaltest.NewMockListener(os.Interrupt)
+		closed := make(chan struct{})
+		serverA := newReadyServer(ctrl, "Server A")
+		serverA.EXPECT().Listen(gomock.Any()).Do(func(context.Context) {
+			go listener.Send(os.Interrupt)
+			<-closed
+		})
+		serverA.EXPECT().Close(gomock.Any()).Do(func(context.Context) {
+			close(closed)
+		})
+
+		runner := services.NewRunner(services.WithListenerBuilder(func() signals.Listener {
+			return listener
+		}))
+		Expect(runner.Run(ctx, serverA)).To(MatchError(services.ErrStartCancelledBySignal))
+	})
+})
+
+func currentState(runner *services.Runner, name string) services.ServiceState {
+	state, _ := runner.State(name)
+	return state
+}
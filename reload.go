@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"syscall"
+
+	signals "github.com/jamillosantos/go-os-signals"
+)
+
+var (
+	// DefaultReloadSignals is the list of signals that makes the Runner reload the services when WithReloadSignals is
+	// used without signals.
+	DefaultReloadSignals = []os.Signal{syscall.SIGHUP}
+)
+
+// Reloadable describes a service whose configuration can be reloaded while it is running, without restarting it.
+//
+// Reload must keep the previous configuration active when it fails.
+type Reloadable interface {
+	// Reload will reload the configuration.
+	Reload(ctx context.Context) error
+}
+
+// ReloadReporter is a Reporter that is also notified before and after each service is reloaded (check Runner.Reload).
+type ReloadReporter interface {
+	Reporter
+	BeforeReload(context.Context, Service)
+	AfterReload(context.Context, Service, error)
+}
+
+// ReloadError is the error recorded when a Reloadable service fails to reload. It wraps the error returned by
+// Reloadable.Reload.
+type ReloadError struct {
+	Service Service
+	Err     error
+}
+
+func (err *ReloadError) Error() string {
+	return fmt.Sprintf("failed reloading %s: %s", serviceName(err.Service), err.Err)
+}
+
+// Unwrap returns the error returned by Reloadable.Reload.
+func (err *ReloadError) Unwrap() error {
+	return err.Err
+}
+
+// WithReloadSignals is a StarterOption that makes the Runner reload the services (check Runner.Reload) whenever one of
+// the given signals is received. If no signal is given, DefaultReloadSignals is used.
+//
+// The signals are listened from the first Runner.Run until Runner.Finish is called.
+func WithReloadSignals(ss ...os.Signal) StarterOption {
+	if len(ss) == 0 {
+		ss = DefaultReloadSignals
+	}
+	return WithReloadListenerBuilder(func() signals.Listener {
+		return signals.NewListener(ss...)
+	})
+}
+
+// WithReloadListenerBuilder is a StarterOption that sets the builder of the listener of the signals that make the
+// Runner reload the services. Check WithReloadSignals.
+func WithReloadListenerBuilder(builder func() signals.Listener) StarterOption {
+	return func(manager *Runner) {
+		manager.reloadListenerBuilder = builder
+	}
+}
+
+// Reload calls Reloadable.Reload on every running service that implements it, one at a time, respecting their
+// dependencies (check Dependent). The Runner does not restart, nor change the state of, the services.
+//
+// If a service fails to reload, Reload moves on to the next one and, in the end, returns all failures aggregated in a
+// MultiErrors (or the error itself, when there is only one). Each failure is a ReloadError identifying the service.
+// Panics are recovered as a PanicError. If the Reporter implements ReloadReporter, it is notified before and after
+// each service is reloaded.
+//
+// Concurrent calls are executed one after the other.
+func (r *Runner) Reload(ctx context.Context) error {
+	r.reloadMutex.Lock()
+	defer r.reloadMutex.Unlock()
+
+	reporter, hasReporter := r.reporter.(ReloadReporter)
+
+	errs := make([]error, 0)
+	for _, service := range r.reloadableServices() {
+		reloadable, _ := As[Reloadable](service)
+		if hasReporter {
+			reporter.BeforeReload(ctx, service)
+		}
+		err := recoverPanic(ctx, r.reporter, service, "reload", func() error {
+			return reloadable.Reload(ctx)
+		})
+		if hasReporter {
+			reporter.AfterReload(ctx, service, err)
+		}
+		if err != nil {
+			errs = append(errs, &ReloadError{
+				Service: service,
+				Err:     err,
+			})
+		}
+	}
+	return joinErrors(errs)
+}
+
+// reloadableServices returns the running services that implement Reloadable in dependency order. If the order cannot
+// be resolved, the order they were given to Run is used.
+func (r *Runner) reloadableServices() []Service {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	order := r.stateOrder
+	if levels, err := dependencyLevels(r.stateOrder, nil); err == nil {
+		order = make([]Service, 0, len(r.stateOrder))
+		for _, level := range levels {
+			order = append(order, level...)
+		}
+	}
+
+	result := make([]Service, 0)
+	for _, service := range order {
+		if _, ok := As[Reloadable](service); !ok || r.states[service].state != StateRunning {
+			continue
+		}
+		result = append(result, service)
+	}
+	return result
+}
+
+// startReloading starts listening the reload signals, if it is enabled and not listening yet.
+func (r *Runner) startReloading() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.reloadListenerBuilder == nil || r.reloadListener != nil {
+		return
+	}
+	listener := r.reloadListenerBuilder()
+	r.reloadListener = listener
+	go func() {
+		for sig := range listener.Receive() {
+			if r.reporter != nil {
+				r.reporter.SignalReceived(sig)
+			}
+			_ = r.Reload(context.Background())
+		}
+	}()
+}
+
+// stopReloading stops listening the reload signals.
+func (r *Runner) stopReloading() {
+	r.mutex.Lock()
+	listener := r.reloadListener
+	r.reloadListener = nil
+	r.mutex.Unlock()
+
+	if listener != nil {
+		listener.Stop()
+	}
+}
@@ -0,0 +1,155 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"syscall"
+
+	"github.com/golang/mock/gomock"
+	signals "github.com/jamillosantos/go-os-signals"
+	"github.com/jamillosantos/go-os-signals/signaltest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/setare/go-services"
+)
+
+type reloadableResource struct {
+	*MockResource
+	*MockDependent
+	*MockReloadable
+}
+
+func newReloadableResource(ctrl *gomock.Controller, name string, dependsOn ...services.Service) *reloadableResource {
+	r := &reloadableResource{
+		MockResource:   NewMockResource(ctrl),
+		MockDependent:  NewMockDependent(ctrl),
+		MockReloadable: NewMockReloadable(ctrl),
+	}
+	r.MockResource.EXPECT().Name().Return(name).AnyTimes()
+	r.MockDependent.EXPECT().DependsOn().Return(dependsOn).AnyTimes()
+	r.MockResource.EXPECT().Start(gomock.Any()).AnyTimes()
+	return r
+}
+
+var _ = Describe("Reload", func() {
+	It("should reload the running services respecting their dependencies", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		// C <- B <- A
+		serviceC := newReloadableResource(ctrl, "Service C")
+		serviceB := newReloadableResource(ctrl, "Service B", serviceC)
+		serviceA := newReloadableResource(ctrl, "Service A", serviceB)
+
+		reporter := NewMockReloadReporter(ctrl)
+		reporter.EXPECT().BeforeStart(gomock.Any(), gomock.Any()).AnyTimes()
+		reporter.EXPECT().AfterStart(gomock.Any(), gomock.Any(), nil).AnyTimes()
+		gomock.InOrder(
+			reporter.EXPECT().BeforeReload(gomock.Any(), serviceC),
+			serviceC.MockReloadable.EXPECT().Reload(gomock.Any()),
+			reporter.EXPECT().AfterReload(gomock.Any(), serviceC, nil),
+			reporter.EXPECT().BeforeReload(gomock.Any(), serviceB),
+			serviceB.MockReloadable.EXPECT().Reload(gomock.Any()),
+			reporter.EXPECT().AfterReload(gomock.Any(), serviceB, nil),
+			reporter.EXPECT().BeforeReload(gomock.Any(), serviceA),
+			serviceA.MockReloadable.EXPECT().Reload(gomock.Any()),
+			reporter.EXPECT().AfterReload(gomock.Any(), serviceA, nil),
+		)
+
+		runner := services.NewRunner(services.WithReporter(reporter))
+		Expect(runner.Run(ctx, serviceA, serviceB, serviceC)).To(Succeed())
+		Expect(runner.Reload(ctx)).To(Succeed())
+	})
+
+	It("should keep reloading when a service fails", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		serviceA := newReloadableResource(ctrl, "Service A")
+		serviceB := newReloadableResource(ctrl, "Service B")
+		serviceC := NewMockResource(ctrl)
+		serviceC.EXPECT().Name().Return("Service C").AnyTimes()
+		serviceC.EXPECT().Start(gomock.Any())
+
+		wantErr := errors.New("invalid configuration")
+		gomock.InOrder(
+			serviceA.MockReloadable.EXPECT().Reload(gomock.Any()).Return(wantErr),
+			serviceB.MockReloadable.EXPECT().Reload(gomock.Any()),
+		)
+
+		runner := services.NewRunner()
+		Expect(runner.Run(ctx, serviceA, serviceB, serviceC)).To(Succeed())
+
+		err := runner.Reload(ctx)
+		Expect(err).To(MatchError(wantErr))
+		Expect(err.Error()).To(Equal("failed reloading Service A: invalid configuration"))
+
+		var reloadErr *services.ReloadError
+		Expect(errors.As(err, &reloadErr)).To(BeTrue())
+		Expect(reloadErr.Service).To(Equal(serviceA))
+
+		// The service keeps running with its previous configuration.
+		Expect(currentState(runner, "Service A")).To(Equal(services.StateRunning))
+	})
+
+	It("should not reload services that are not running", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		serviceA := newReloadableResource(ctrl, "Service A")
+		serviceA.MockResource.EXPECT().Stop(gomock.Any())
+
+		runner := services.NewRunner()
+		Expect(runner.Run(ctx, serviceA)).To(Succeed())
+		Expect(runner.Finish(ctx)).To(Succeed())
+		Expect(runner.Reload(ctx)).To(Succeed())
+	})
+
+	It("should reload when the reload signal is received until finished", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		listener := signaltest.NewMockListener(syscall.SIGHUP)
+		builds := 0
+
+		serviceA := newReloadableResource(ctrl, "Service A")
+		serviceA.MockResource.EXPECT().Stop(gomock.Any())
+		reloaded := make(chan struct{})
+		serviceA.MockReloadable.EXPECT().Reload(gomock.Any())
+
+		reporter := NewMockReloadReporter(ctrl)
+		reporter.EXPECT().BeforeStart(gomock.Any(), serviceA)
+		reporter.EXPECT().AfterStart(gomock.Any(), serviceA, nil)
+		reporter.EXPECT().SignalReceived(syscall.SIGHUP)
+		reporter.EXPECT().BeforeReload(gomock.Any(), serviceA)
+		reporter.EXPECT().AfterReload(gomock.Any(), serviceA, nil).Do(func(context.Context, services.Service, error) {
+			close(reloaded)
+		})
+		reporter.EXPECT().BeforeStop(gomock.Any(), serviceA)
+		reporter.EXPECT().AfterStop(gomock.Any(), serviceA, nil)
+
+		runner := services.NewRunner(
+			services.WithReporter(reporter),
+			services.WithReloadListenerBuilder(func() signals.Listener {
+				builds++
+				return listener
+			}),
+		)
+		Expect(runner.Run(ctx, serviceA)).To(Succeed())
+
+		listener.Send(syscall.SIGHUP)
+		Eventually(reloaded).Should(BeClosed())
+
+		Expect(runner.Finish(ctx)).To(Succeed())
+		Expect(builds).To(Equal(1))
+	})
+})
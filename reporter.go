@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"os"
+	"time"
 )
 
 // Reporter will be called Before and After some actions by a `Runner`.
@@ -17,7 +18,42 @@ type Reporter interface {
 	SignalReceived(os.Signal)
 }
 
+// RetrierReporter is the Reporter used by a ResourceServiceRetrier. Besides the Reporter methods, it is notified
+// before each attempt with the attempt number (starting from 1).
 type RetrierReporter interface {
 	Reporter
 	BeforeRetry(context.Context, Service, int)
 }
+
+// AttemptReporter is a RetrierReporter that is also notified after each attempt with its error and how long the
+// retrier will wait before the next attempt. When there is no next attempt, the delay is `backoff.Stop`.
+type AttemptReporter interface {
+	RetrierReporter
+	AfterRetry(context.Context, Service, int, error, time.Duration)
+}
+
+// SupervisorReporter is the Reporter used by a ServerSupervisor. Besides the Reporter methods, it is notified before
+// each restart of a Server with how many times it was restarted and the error that caused the restart.
+type SupervisorReporter interface {
+	Reporter
+	BeforeRestart(context.Context, Service, int, error)
+}
+
+// PhaseReporter is a Reporter that is also notified at the boundaries of each shutdown phase (check
+// WithShutdownPhase). The error given to AfterShutdownPhase aggregates the failures of the servers of the phase.
+type PhaseReporter interface {
+	Reporter
+	BeforeShutdownPhase(context.Context, string)
+	AfterShutdownPhase(context.Context, string, error)
+}
+
+// RunReporter is a Reporter that is also notified when Runner.Run and Runner.Finish begin and end. The ctx returned by
+// BeforeRun (or BeforeFinish) replaces the given one until the call ends, so it is given to the services and to all
+// the events reported in between. This allows the reporter to carry data, like a trace span, along the call.
+type RunReporter interface {
+	Reporter
+	BeforeRun(context.Context, []Service) context.Context
+	AfterRun(context.Context, error)
+	BeforeFinish(context.Context) context.Context
+	AfterFinish(context.Context, error)
+}
@@ -2,6 +2,10 @@ package services
 
 import (
 	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/cenkalti/backoff/v4"
 )
@@ -12,29 +16,48 @@ type ResourceServiceRetrier struct {
 	service  Resource
 	reporter RetrierReporter
 	backoff  backoff.BackOff
+	policy   retryPolicy
+	retries  atomic.Int32
 }
 
-// RetrierBuilder is the helper for building `ResourceServiceRetrier`.
+// retryPolicy defines how the attempts are made.
+type retryPolicy struct {
+	maxAttempts    int
+	attemptTimeout time.Duration
+	permanent      func(error) bool
+}
+
+// RetrierBuilder is the helper for building `ResourceServiceRetrier` and `ServerServiceRetrier`.
 type RetrierBuilder struct {
-	backoff  backoff.BackOff
-	reporter RetrierReporter
+	newBackoff   func() backoff.BackOff
+	reporter     RetrierReporter
+	policy       retryPolicy
+	retryLoad    bool
+	runningAfter time.Duration
 }
 
 // Retrier returns a new `RetrierBuilder` instance.
 func Retrier() *RetrierBuilder {
 	return &RetrierBuilder{
-		backoff: backoff.NewExponentialBackOff(),
+		newBackoff: func() backoff.BackOff {
+			return backoff.NewExponentialBackOff()
+		},
+		runningAfter: time.Second,
 	}
 }
 
 // Build creates a new `ResourceServiceRetrier` with
+//
+// The Runner looks through the returned service (check Unwrap) for the optional interfaces of the given service, such
+// as HealthChecker and Reloadable.
 func (builder *RetrierBuilder) Build(service Resource) Resource {
 	sr := &ResourceServiceRetrier{
 		service:  service,
 		reporter: builder.reporter,
-		backoff:  builder.backoff,
+		backoff:  builder.newBackoff(),
+		policy:   builder.policy,
 	}
-	if configurable, ok := service.(Configurable); ok {
+	if configurable := builder.configurable(service); configurable != nil {
 		return struct {
 			*ResourceServiceRetrier
 			Configurable
@@ -46,23 +69,180 @@ func (builder *RetrierBuilder) Build(service Resource) Resource {
 	return sr
 }
 
+// BuildServer creates a new `ServerServiceRetrier` that retries the `Listen` of the given server while it fails
+// before being considered running (check RunningAfter).
+//
+// The Runner looks through the returned server (check Unwrap) for the optional interfaces of the given server, such as
+// ReadyNotifier and HealthChecker.
+func (builder *RetrierBuilder) BuildServer(server Server) Server {
+	sr := &ServerServiceRetrier{
+		server:       server,
+		reporter:     builder.reporter,
+		backoff:      builder.newBackoff(),
+		policy:       builder.policy,
+		runningAfter: builder.runningAfter,
+	}
+	// A running server would be interrupted by the attempt timeout.
+	sr.policy.attemptTimeout = 0
+	if configurable := builder.configurable(server); configurable != nil {
+		return struct {
+			*ServerServiceRetrier
+			Configurable
+		}{
+			sr,
+			configurable,
+		}
+	}
+	return sr
+}
+
+// configurable returns the Configurable implementation of the given service, wrapping it to retry the Load when
+// RetryLoad is set. If the service does not implement Configurable, nil is returned.
+func (builder *RetrierBuilder) configurable(service Service) Configurable {
+	configurable, ok := service.(Configurable)
+	if !ok {
+		return nil
+	}
+	if !builder.retryLoad {
+		return configurable
+	}
+	return &configurableRetrier{
+		service:      service,
+		configurable: configurable,
+		reporter:     builder.reporter,
+		backoff:      builder.newBackoff(),
+		policy:       builder.policy,
+	}
+}
+
 // Backoff set the timeout for the `Retrier`.
+//
+// Since the services built are started in parallel, each one of them uses its own copy of the given backoff. The
+// backoffs of this package are copied; any other implementation is shared by the services built, which take turns
+// using it. Use BackoffFactory to give each one its own instance instead.
 func (builder *RetrierBuilder) Backoff(value backoff.BackOff) *RetrierBuilder {
-	builder.backoff = value
+	builder.newBackoff = copyBackOff(value)
+	return builder
+}
+
+// BackoffFactory sets the function that creates the backoff of each service built by the `Retrier`.
+func (builder *RetrierBuilder) BackoffFactory(value func() backoff.BackOff) *RetrierBuilder {
+	builder.newBackoff = value
 	return builder
 }
 
-// Reporter set the reporter for the `Retrier`.
+// copyBackOff returns a function that creates copies of the given backoff. When it cannot be copied, the returned
+// function returns the backoff itself, guarded by a mutex.
+func copyBackOff(b backoff.BackOff) func() backoff.BackOff {
+	switch value := b.(type) {
+	case *backoff.ExponentialBackOff:
+		original := *value
+		return func() backoff.BackOff {
+			b := original
+			return &b
+		}
+	case *backoff.ConstantBackOff:
+		original := *value
+		return func() backoff.BackOff {
+			b := original
+			return &b
+		}
+	case *backoff.ZeroBackOff, *backoff.StopBackOff:
+		return func() backoff.BackOff {
+			return value
+		}
+	}
+	shared := &lockedBackOff{backoff: b}
+	return func() backoff.BackOff {
+		return shared
+	}
+}
+
+// lockedBackOff serializes the access to a backoff shared by many retriers.
+type lockedBackOff struct {
+	mutex   sync.Mutex
+	backoff backoff.BackOff
+}
+
+func (b *lockedBackOff) NextBackOff() time.Duration {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.backoff.NextBackOff()
+}
+
+func (b *lockedBackOff) Reset() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.backoff.Reset()
+}
+
+// Reporter set the reporter for the `Retrier`. If it implements AttemptReporter, it is also notified after each attempt.
 func (builder *RetrierBuilder) Reporter(value RetrierReporter) *RetrierBuilder {
 	builder.reporter = value
 	return builder
 }
 
+// MaxAttempts sets how many times, at most, the `Retrier` will try. Zero, or a negative value, means that it will try
+// until the backoff gives up (or the ctx is cancelled).
+func (builder *RetrierBuilder) MaxAttempts(value int) *RetrierBuilder {
+	builder.policy.maxAttempts = value
+	return builder
+}
+
+// AttemptTimeout sets how long each attempt can take. When exceeded, the ctx given to the attempt is cancelled. Zero,
+// or a negative value, means that the attempts have no timeout.
+//
+// The ctx given to each attempt is cancelled when the attempt finishes. So, the wrapped service should not keep it.
+func (builder *RetrierBuilder) AttemptTimeout(value time.Duration) *RetrierBuilder {
+	builder.policy.attemptTimeout = value
+	return builder
+}
+
+// Permanent sets the function that classifies the errors that should not be retried. Errors wrapped by
+// `backoff.Permanent` are never retried, regardless of this function.
+func (builder *RetrierBuilder) Permanent(value func(error) bool) *RetrierBuilder {
+	builder.policy.permanent = value
+	return builder
+}
+
+// RetryLoad makes the `Retrier` also retry the `Load` of services that implement Configurable, using the same backoff,
+// policy and reporter.
+func (builder *RetrierBuilder) RetryLoad() *RetrierBuilder {
+	builder.retryLoad = true
+	return builder
+}
+
+// RunningAfter sets how long the `Listen` of a server must run before the server is considered running. A `Listen`
+// that fails before that is retried; after that, its error is returned. The default is 1 second.
+func (builder *RetrierBuilder) RunningAfter(value time.Duration) *RetrierBuilder {
+	builder.runningAfter = value
+	return builder
+}
+
 // Name will return a human identifiable name for this service. Ex: Postgresql Connection.
 func (retrier *ResourceServiceRetrier) Name() string {
 	return retrier.service.Name()
 }
 
+// DependsOn returns the dependencies declared by the wrapped service. If it does not implement Dependent, nil is
+// returned.
+func (retrier *ResourceServiceRetrier) DependsOn() []Service {
+	if dependent, ok := retrier.service.(Dependent); ok {
+		return dependent.DependsOn()
+	}
+	return nil
+}
+
+// Unwrap returns the wrapped service.
+func (retrier *ResourceServiceRetrier) Unwrap() Service {
+	return retrier.service
+}
+
+// Retries returns how many times the last start of the service was retried.
+func (retrier *ResourceServiceRetrier) Retries() int {
+	return int(retrier.retries.Load())
+}
+
 // Stop will stop this service.
 //
 // For most implementations it will be blocking and should return only when the service finishes stopping.
@@ -73,14 +253,202 @@ func (retrier *ResourceServiceRetrier) Stop(ctx context.Context) error {
 }
 
 // Start implements the logic of starting a service. If it fails, it should use the configuration to retry.
+//
+// The retries stop when the ctx is cancelled, when the error is permanent (check RetrierBuilder.Permanent), when the
+// maximum attempts is reached or when the backoff gives up. Then, the error of the last attempt is returned. If the
+// ctx was cancelled, its error is returned instead.
 func (retrier *ResourceServiceRetrier) Start(ctx context.Context) error {
-	count := 0
-	err := backoff.Retry(func() error {
-		count++
-		if retrier.reporter != nil {
-			retrier.reporter.BeforeRetry(ctx, retrier.service, count)
-		}
-		return retrier.service.Start(ctx)
-	}, retrier.backoff)
+	return retrier.policy.retry(ctx, retrier.service, retrier.backoff, retrier.reporter, &retrier.retries, retrier.service.Start)
+}
+
+// configurableRetrier wraps a Configurable retrying its Load.
+type configurableRetrier struct {
+	service      Service
+	configurable Configurable
+	reporter     RetrierReporter
+	backoff      backoff.BackOff
+	policy       retryPolicy
+}
+
+// Load calls the Load of the wrapped Configurable, retrying it when it fails.
+func (retrier *configurableRetrier) Load(ctx context.Context) error {
+	return retrier.policy.retry(ctx, retrier.service, retrier.backoff, retrier.reporter, nil, retrier.configurable.Load)
+}
+
+// ServerServiceRetrier wraps a `Server` in order to provide functionality for retrying its `Listen` in case it fails
+// before the server is considered running (check RetrierBuilder.RunningAfter).
+//
+// Since `Listen` blocks while the server is running, the attempt timeout (check RetrierBuilder.AttemptTimeout) is not
+// applied.
+type ServerServiceRetrier struct {
+	server       Server
+	reporter     RetrierReporter
+	backoff      backoff.BackOff
+	policy       retryPolicy
+	runningAfter time.Duration
+
+	mutex      sync.Mutex
+	listening  bool
+	closing    bool
+	cancelFunc context.CancelFunc
+	retries    atomic.Int32
+}
+
+// Name will return a human identifiable name for this service.
+func (retrier *ServerServiceRetrier) Name() string {
+	return retrier.server.Name()
+}
+
+// DependsOn returns the dependencies declared by the wrapped server. If it does not implement Dependent, nil is
+// returned.
+func (retrier *ServerServiceRetrier) DependsOn() []Service {
+	if dependent, ok := retrier.server.(Dependent); ok {
+		return dependent.DependsOn()
+	}
+	return nil
+}
+
+// Unwrap returns the wrapped server.
+func (retrier *ServerServiceRetrier) Unwrap() Service {
+	return retrier.server
+}
+
+// Listen calls the `Listen` of the wrapped server, retrying it while it fails before the server is considered running.
+// Once running, the error of the wrapped `Listen` is returned as is. If Close is called, nil is returned.
+//
+// If the retrier is already listening, ErrAlreadyListening is returned. An ErrAlreadyListening of the wrapped server is
+// not retried.
+func (retrier *ServerServiceRetrier) Listen(ctx context.Context) error {
+	retrier.mutex.Lock()
+	if retrier.listening {
+		retrier.mutex.Unlock()
+		return ErrAlreadyListening
+	}
+	retryCtx, cancelFunc := context.WithCancel(ctx)
+	defer cancelFunc()
+	retrier.listening = true
+	retrier.closing = false
+	retrier.cancelFunc = cancelFunc
+	retrier.mutex.Unlock()
+
+	defer func() {
+		retrier.mutex.Lock()
+		retrier.listening = false
+		retrier.cancelFunc = nil
+		retrier.mutex.Unlock()
+	}()
+
+	err := retrier.policy.retry(retryCtx, retrier.server, retrier.backoff, retrier.reporter, &retrier.retries, func(context.Context) error {
+		if retrier.isClosing() {
+			return nil
+		}
+
+		startedAt := time.Now()
+		err := retrier.server.Listen(ctx)
+		if err != nil && (retrier.isClosing() || time.Since(startedAt) >= retrier.runningAfter) {
+			// The server was already running, so it is not retried.
+			return backoff.Permanent(err)
+		}
+		if errors.Is(err, ErrAlreadyListening) {
+			// The wrapped server is listening on its own, retrying would not change that.
+			return backoff.Permanent(err)
+		}
+		return err
+	})
+	if retrier.isClosing() {
+		return nil
+	}
 	return err
 }
+
+// Retries returns how many times the last `Listen` of the server was retried.
+func (retrier *ServerServiceRetrier) Retries() int {
+	return int(retrier.retries.Load())
+}
+
+// Close stops retrying and closes the wrapped server.
+func (retrier *ServerServiceRetrier) Close(ctx context.Context) error {
+	retrier.mutex.Lock()
+	retrier.closing = true
+	if retrier.cancelFunc != nil {
+		retrier.cancelFunc()
+	}
+	retrier.mutex.Unlock()
+
+	return retrier.server.Close(ctx)
+}
+
+func (retrier *ServerServiceRetrier) isClosing() bool {
+	retrier.mutex.Lock()
+	defer retrier.mutex.Unlock()
+	return retrier.closing
+}
+
+// retry calls fn until it succeeds or the policy gives up. The given reporter, if any, is notified before each attempt
+// and, if it implements AttemptReporter, after each attempt. So are the subscriptions of the Runner that gave the ctx (check Runner.Events). The given retries, if
+// any, is updated with how many times fn was retried.
+func (policy retryPolicy) retry(ctx context.Context, service Service, b backoff.BackOff, reporter RetrierReporter, retries *atomic.Int32, fn func(context.Context) error) error {
+	b = backoff.WithContext(b, ctx)
+	b.Reset()
+	attemptReporter, hasAttemptReporter := reporter.(AttemptReporter)
+	events := eventBrokerFromContext(ctx)
+
+	for attempt := 1; ; attempt++ {
+		if retries != nil {
+			retries.Store(int32(attempt - 1))
+		}
+		if reporter != nil {
+			reporter.BeforeRetry(ctx, service, attempt)
+		}
+		if events != nil {
+			events.BeforeRetry(ctx, service, attempt)
+		}
+		err := policy.attempt(ctx, fn)
+
+		next := backoff.Stop
+		var permanent *backoff.PermanentError
+		switch {
+		case err == nil:
+		case errors.As(err, &permanent):
+			err = permanent.Err
+		case policy.permanent != nil && policy.permanent(err):
+		case policy.maxAttempts > 0 && attempt >= policy.maxAttempts:
+		default:
+			next = b.NextBackOff()
+		}
+
+		if hasAttemptReporter {
+			attemptReporter.AfterRetry(ctx, service, attempt, err, next)
+		}
+		if events != nil {
+			events.AfterRetry(ctx, service, attempt, err, next)
+		}
+		if err == nil {
+			return nil
+		}
+		if next == backoff.Stop {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			return err
+		}
+
+		timer := time.NewTimer(next)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt calls fn applying the attempt timeout, if any.
+func (policy retryPolicy) attempt(ctx context.Context, fn func(context.Context) error) error {
+	if policy.attemptTimeout <= 0 {
+		return fn(ctx)
+	}
+	ctx, cancelFunc := context.WithTimeout(ctx, policy.attemptTimeout)
+	defer cancelFunc()
+	return fn(ctx)
+}
@@ -1,16 +1,23 @@
 package services_test
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"os"
+	"sync"
+	"testing"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
 	"github.com/golang/mock/gomock"
+	signals "github.com/jamillosantos/go-os-signals"
+	"github.com/jamillosantos/go-os-signals/signaltest"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	"github.com/setare/go-services"
+	"github.com/setare/go-services/servicestest"
 )
 
 var _ = Describe("Retrier", func() {
@@ -44,18 +51,22 @@ var _ = Describe("Retrier", func() {
 
 			serviceA := NewMockResource(ctrl)
 
-			reporter := NewMockRetrierReporter(ctrl)
+			reporter := NewMockAttemptReporter(ctrl)
 
 			randomErr := errors.New("any error")
 			gomock.InOrder(
 				reporter.EXPECT().BeforeRetry(gomock.Any(), serviceA, 1),
 				serviceA.EXPECT().Start(gomock.Any()).Return(randomErr),
+				reporter.EXPECT().AfterRetry(gomock.Any(), serviceA, 1, randomErr, time.Millisecond*50),
 				reporter.EXPECT().BeforeRetry(gomock.Any(), serviceA, 2),
 				serviceA.EXPECT().Start(gomock.Any()).Return(randomErr),
+				reporter.EXPECT().AfterRetry(gomock.Any(), serviceA, 2, randomErr, time.Millisecond*50),
 				reporter.EXPECT().BeforeRetry(gomock.Any(), serviceA, 3),
 				serviceA.EXPECT().Start(gomock.Any()).Return(randomErr),
+				reporter.EXPECT().AfterRetry(gomock.Any(), serviceA, 3, randomErr, time.Millisecond*50),
 				reporter.EXPECT().BeforeRetry(gomock.Any(), serviceA, 4),
 				serviceA.EXPECT().Start(gomock.Any()).Return(nil),
+				reporter.EXPECT().AfterRetry(gomock.Any(), serviceA, 4, nil, backoff.Stop),
 			)
 
 			serviceARetrier := services.Retrier().Reporter(reporter).Backoff(backoff.NewConstantBackOff(time.Millisecond * 50)).Build(serviceA)
@@ -88,5 +99,513 @@ var _ = Describe("Retrier", func() {
 			runner := services.NewRunner()
 			Expect(runner.Run(ctx, serviceARetrier)).To(Succeed())
 		})
+
+		It("should stop retrying when the ctx is cancelled", func() {
+			ctrl := createController()
+			defer ctrl.Finish()
+
+			ctx, cancelFunc := context.WithCancel(context.TODO())
+			defer cancelFunc()
+
+			serviceA := NewMockResource(ctrl)
+			serviceA.EXPECT().Start(gomock.Any()).Return(errors.New("any error")).MinTimes(1)
+
+			go func() {
+				time.Sleep(time.Millisecond * 50)
+				cancelFunc()
+			}()
+
+			serviceARetrier := services.Retrier().Backoff(backoff.NewConstantBackOff(time.Millisecond * 20)).Build(serviceA)
+			startedAt := time.Now()
+			Expect(serviceARetrier.Start(ctx)).To(MatchError(context.Canceled))
+			Expect(time.Since(startedAt)).To(BeNumerically("~", time.Millisecond*50, time.Millisecond*20))
+		})
+
+		It("should stop retrying when a signal is received", func() {
+			ctrl := createController()
+			defer ctrl.Finish()
+
+			ctx := context.TODO()
+
+			serviceA := NewMockResource(ctrl)
+			serviceA.EXPECT().Start(gomock.Any()).Return(errors.New("any error")).MinTimes(1)
+
+			listener := signaltest.NewMockListener(os.Interrupt)
+			go func() {
+				time.Sleep(time.Millisecond * 50)
+				listener.Send(os.Interrupt)
+			}()
+
+			serviceARetrier := services.Retrier().Backoff(backoff.NewConstantBackOff(time.Millisecond * 20)).Build(serviceA)
+			runner := services.NewRunner(services.WithListenerBuilder(func() signals.Listener {
+				return listener
+			}))
+			Expect(runner.Run(ctx, serviceARetrier)).To(MatchError(services.ErrStartCancelledBySignal))
+		})
+
+		It("should notify only the attempts to a RetrierReporter that is not an AttemptReporter", func() {
+			ctrl := createController()
+			defer ctrl.Finish()
+
+			ctx := context.TODO()
+
+			serviceA := NewMockResource(ctrl)
+			reporter := NewMockRetrierReporter(ctrl)
+
+			gomock.InOrder(
+				reporter.EXPECT().BeforeRetry(gomock.Any(), serviceA, 1),
+				serviceA.EXPECT().Start(gomock.Any()).Return(errors.New("any error")),
+				reporter.EXPECT().BeforeRetry(gomock.Any(), serviceA, 2),
+				serviceA.EXPECT().Start(gomock.Any()),
+			)
+
+			serviceARetrier := services.Retrier().
+				Reporter(reporter).
+				Backoff(backoff.NewConstantBackOff(time.Millisecond)).
+				Build(serviceA)
+			Expect(serviceARetrier.Start(ctx)).To(Succeed())
+		})
+
+		It("should give up after the max attempts", func() {
+			ctrl := createController()
+			defer ctrl.Finish()
+
+			ctx := context.TODO()
+
+			serviceA := NewMockResource(ctrl)
+			reporter := NewMockAttemptReporter(ctrl)
+
+			randomErr := errors.New("any error")
+			gomock.InOrder(
+				reporter.EXPECT().BeforeRetry(gomock.Any(), serviceA, 1),
+				serviceA.EXPECT().Start(gomock.Any()).Return(randomErr),
+				reporter.EXPECT().AfterRetry(gomock.Any(), serviceA, 1, randomErr, time.Millisecond),
+				reporter.EXPECT().BeforeRetry(gomock.Any(), serviceA, 2),
+				serviceA.EXPECT().Start(gomock.Any()).Return(randomErr),
+				reporter.EXPECT().AfterRetry(gomock.Any(), serviceA, 2, randomErr, backoff.Stop),
+			)
+
+			serviceARetrier := services.Retrier().
+				Reporter(reporter).
+				Backoff(backoff.NewConstantBackOff(time.Millisecond)).
+				MaxAttempts(2).
+				Build(serviceA)
+			Expect(serviceARetrier.Start(ctx)).To(MatchError(randomErr))
+		})
+
+		It("should not retry permanent errors", func() {
+			ctrl := createController()
+			defer ctrl.Finish()
+
+			ctx := context.TODO()
+
+			errInvalidCredentials := errors.New("invalid credentials")
+
+			serviceA := NewMockResource(ctrl)
+			serviceA.EXPECT().Start(gomock.Any()).Return(errInvalidCredentials)
+			serviceB := NewMockResource(ctrl)
+			serviceB.EXPECT().Start(gomock.Any()).Return(backoff.Permanent(errInvalidCredentials))
+
+			serviceARetrier := services.Retrier().
+				Backoff(backoff.NewConstantBackOff(time.Millisecond)).
+				Permanent(func(err error) bool {
+					return errors.Is(err, errInvalidCredentials)
+				}).
+				Build(serviceA)
+			Expect(serviceARetrier.Start(ctx)).To(MatchError(errInvalidCredentials))
+
+			serviceBRetrier := services.Retrier().Backoff(backoff.NewConstantBackOff(time.Millisecond)).Build(serviceB)
+			Expect(serviceBRetrier.Start(ctx)).To(Equal(errInvalidCredentials))
+		})
+
+		It("should cancel an attempt that takes too long", func() {
+			ctrl := createController()
+			defer ctrl.Finish()
+
+			ctx := context.TODO()
+
+			serviceA := NewMockResource(ctrl)
+			gomock.InOrder(
+				serviceA.EXPECT().Start(gomock.Any()).DoAndReturn(func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				}),
+				serviceA.EXPECT().Start(gomock.Any()).Return(nil),
+			)
+
+			serviceARetrier := services.Retrier().
+				Backoff(backoff.NewConstantBackOff(time.Millisecond)).
+				AttemptTimeout(time.Millisecond * 50).
+				Build(serviceA)
+			startedAt := time.Now()
+			Expect(serviceARetrier.Start(ctx)).To(Succeed())
+			Expect(time.Since(startedAt)).To(BeNumerically("~", time.Millisecond*50, time.Millisecond*20))
+		})
+
+		It("should give each service built its own backoff", func() {
+			ctrl := createController()
+			defer ctrl.Finish()
+
+			ctx := context.TODO()
+
+			serviceC := NewMockResource(ctrl)
+			serviceC.EXPECT().Start(gomock.Any())
+
+			randomErr := errors.New("any error")
+			newService := func(name string) *dependentResource {
+				service := newDependentResource(ctrl, name)
+				service.dependsOn(serviceC)
+				gomock.InOrder(
+					service.MockResource.EXPECT().Start(gomock.Any()).Return(randomErr).Times(3),
+					service.MockResource.EXPECT().Start(gomock.Any()).Return(nil),
+				)
+				return service
+			}
+			serviceA := newService("Service A")
+			serviceB := newService("Service B")
+
+			b := backoff.NewExponentialBackOff()
+			b.InitialInterval = time.Millisecond * 10
+			b.RandomizationFactor = 0
+			b.Multiplier = 2
+			reporter := &delayReporter{delays: make(map[services.Service][]time.Duration)}
+			retrier := services.Retrier().Reporter(reporter).Backoff(b)
+
+			// A and B depend on C, so they are in the same dependency level and are retried in parallel.
+			runner := services.NewRunner()
+			Expect(runner.Run(ctx, serviceC, retrier.Build(serviceA), retrier.Build(serviceB))).To(Succeed())
+			wantDelays := []time.Duration{time.Millisecond * 10, time.Millisecond * 20, time.Millisecond * 40, backoff.Stop}
+			Expect(reporter.delays[serviceA]).To(Equal(wantDelays))
+			Expect(reporter.delays[serviceB]).To(Equal(wantDelays))
+		})
+
+		It("should create the backoff of each service built with the BackoffFactory", func() {
+			ctrl := createController()
+			defer ctrl.Finish()
+
+			ctx := context.TODO()
+
+			serviceA := NewMockResource(ctrl)
+			serviceA.EXPECT().Start(gomock.Any())
+			serviceB := NewMockResource(ctrl)
+			serviceB.EXPECT().Start(gomock.Any())
+
+			created := 0
+			retrier := services.Retrier().BackoffFactory(func() backoff.BackOff {
+				created++
+				return &backoff.ZeroBackOff{}
+			})
+			serviceARetrier := retrier.Build(serviceA)
+			serviceBRetrier := retrier.Build(serviceB)
+			Expect(created).To(Equal(2))
+
+			runner := services.NewRunner()
+			Expect(runner.Run(ctx, serviceARetrier, serviceBRetrier)).To(Succeed())
+		})
+	})
+
+	Context("Server", func() {
+		It("should retry a Server that fails before running", func() {
+			ctrl := createController()
+			defer ctrl.Finish()
+
+			ctx := context.TODO()
+
+			errA := errors.New("address already in use")
+			serverA := newScriptedServer("Server A", errA, errA)
+
+			reporter := NewMockAttemptReporter(ctrl)
+			gomock.InOrder(
+				reporter.EXPECT().BeforeRetry(gomock.Any(), serverA, 1),
+				reporter.EXPECT().AfterRetry(gomock.Any(), serverA, 1, errA, time.Millisecond),
+				reporter.EXPECT().BeforeRetry(gomock.Any(), serverA, 2),
+				reporter.EXPECT().AfterRetry(gomock.Any(), serverA, 2, errA, time.Millisecond),
+				reporter.EXPECT().BeforeRetry(gomock.Any(), serverA, 3),
+				reporter.EXPECT().AfterRetry(gomock.Any(), serverA, 3, nil, backoff.Stop),
+			)
+
+			serverARetrier := services.Retrier().
+				Reporter(reporter).
+				Backoff(backoff.NewConstantBackOff(time.Millisecond)).
+				BuildServer(serverA)
+			Expect(serverARetrier.Name()).To(Equal("Server A"))
+
+			listenErr := make(chan error, 1)
+			go func() {
+				listenErr <- serverARetrier.Listen(ctx)
+			}()
+
+			Eventually(serverA.Listens).Should(Equal(3))
+			Consistently(listenErr, time.Millisecond*50).ShouldNot(Receive())
+
+			Expect(serverARetrier.Close(ctx)).To(Succeed())
+			Eventually(listenErr).Should(Receive(BeNil()))
+		})
+
+		It("should not retry a Server that fails after running", func() {
+			ctx := context.TODO()
+
+			errA := errors.New("connection reset")
+			serverA := newScriptedServer("Server A", errA, errA)
+
+			serverARetrier := services.Retrier().
+				Backoff(backoff.NewConstantBackOff(time.Millisecond)).
+				RunningAfter(time.Millisecond).
+				BuildServer(serverA)
+
+			Expect(serverARetrier.Listen(ctx)).To(MatchError(errA))
+			Expect(serverA.Listens()).To(Equal(1))
+		})
+
+		It("should stop retrying when closed", func() {
+			ctx := context.TODO()
+
+			errA := errors.New("address already in use")
+			serverA := newScriptedServer("Server A", errA)
+
+			serverARetrier := services.Retrier().
+				Backoff(backoff.NewConstantBackOff(time.Hour)).
+				BuildServer(serverA)
+
+			listenErr := make(chan error, 1)
+			go func() {
+				listenErr <- serverARetrier.Listen(ctx)
+			}()
+
+			Eventually(serverA.Listens).Should(Equal(1))
+			Expect(serverARetrier.Close(ctx)).To(Succeed())
+			Eventually(listenErr).Should(Receive(BeNil()))
+			Expect(serverA.Listens()).To(Equal(1))
+		})
+
+		It("should reject a Listen while listening", func() {
+			ctx := context.TODO()
+
+			serverA := newScriptedServer("Server A")
+
+			serverARetrier := services.Retrier().
+				Backoff(backoff.NewConstantBackOff(time.Millisecond)).
+				BuildServer(serverA)
+
+			listenErr := make(chan error, 1)
+			go func() {
+				listenErr <- serverARetrier.Listen(ctx)
+			}()
+
+			Eventually(serverA.Listens).Should(Equal(1))
+			Expect(serverARetrier.Listen(ctx)).To(MatchError(services.ErrAlreadyListening))
+			Expect(serverA.Listens()).To(Equal(1))
+
+			// The first Listen can still be closed.
+			Expect(serverARetrier.Close(ctx)).To(Succeed())
+			Eventually(listenErr).Should(Receive(BeNil()))
+		})
+
+		It("should not retry a Server that is already listening", func() {
+			ctx := context.TODO()
+
+			serverA := newScriptedServer("Server A", services.ErrAlreadyListening)
+
+			serverARetrier := services.Retrier().
+				Backoff(backoff.NewConstantBackOff(time.Millisecond)).
+				BuildServer(serverA)
+
+			Expect(serverARetrier.Listen(ctx)).To(MatchError(services.ErrAlreadyListening))
+			Expect(serverA.Listens()).To(Equal(1))
+		})
+	})
+
+	Context("Configurable", func() {
+		It("should retry loading a Configurable service", func() {
+			ctrl := createController()
+			defer ctrl.Finish()
+
+			ctx := context.TODO()
+
+			serviceA := &struct {
+				*MockResource
+				*MockConfigurable
+			}{
+				MockResource:     NewMockResource(ctrl),
+				MockConfigurable: NewMockConfigurable(ctrl),
+			}
+
+			randomErr := errors.New("config store unavailable")
+			gomock.InOrder(
+				serviceA.MockConfigurable.EXPECT().Load(gomock.Any()).Return(randomErr),
+				serviceA.MockConfigurable.EXPECT().Load(gomock.Any()).Return(randomErr),
+				serviceA.MockConfigurable.EXPECT().Load(gomock.Any()),
+				serviceA.MockResource.EXPECT().Start(gomock.Any()),
+			)
+
+			serviceARetrier := services.Retrier().
+				Backoff(backoff.NewConstantBackOff(time.Millisecond)).
+				RetryLoad().
+				Build(serviceA)
+			runner := services.NewRunner()
+			Expect(runner.Run(ctx, serviceARetrier)).To(Succeed())
+		})
+
+		It("should not retry loading a Configurable service by default", func() {
+			ctrl := createController()
+			defer ctrl.Finish()
+
+			ctx := context.TODO()
+
+			serviceA := &struct {
+				*MockResource
+				*MockConfigurable
+			}{
+				MockResource:     NewMockResource(ctrl),
+				MockConfigurable: NewMockConfigurable(ctrl),
+			}
+
+			randomErr := errors.New("config store unavailable")
+			serviceA.MockConfigurable.EXPECT().Load(gomock.Any()).Return(randomErr)
+
+			serviceARetrier := services.Retrier().Backoff(backoff.NewConstantBackOff(time.Millisecond)).Build(serviceA)
+			runner := services.NewRunner()
+			Expect(runner.Run(ctx, serviceARetrier)).To(MatchError(randomErr))
+		})
+	})
+
+	Context("Optional interfaces", func() {
+		It("should let the Runner use the optional interfaces of the wrapped Resource", func() {
+			ctrl := createController()
+			defer ctrl.Finish()
+
+			ctx := context.TODO()
+
+			serviceA := &struct {
+				*MockResource
+				*MockHealthChecker
+				*MockShutdownTimeouter
+				*MockReloadable
+				*configDumper
+			}{
+				MockResource:          NewMockResource(ctrl),
+				MockHealthChecker:     NewMockHealthChecker(ctrl),
+				MockShutdownTimeouter: NewMockShutdownTimeouter(ctrl),
+				MockReloadable:        NewMockReloadable(ctrl),
+				configDumper:          &configDumper{config: "config"},
+			}
+			serviceA.MockResource.EXPECT().Name().Return("Service A").AnyTimes()
+			wantErr := errors.New("unhealthy")
+			gomock.InOrder(
+				serviceA.MockResource.EXPECT().Start(gomock.Any()),
+				serviceA.MockHealthChecker.EXPECT().Check(gomock.Any()).Return(wantErr),
+				serviceA.MockReloadable.EXPECT().Reload(gomock.Any()),
+				serviceA.MockShutdownTimeouter.EXPECT().ShutdownTimeout().Return(time.Second),
+				serviceA.MockResource.EXPECT().Stop(gomock.Any()),
+			)
+
+			serviceARetrier := services.Retrier().Build(serviceA)
+			Expect(serviceARetrier.(services.Wrapper).Unwrap()).To(Equal(serviceA))
+			_, ok := services.As[services.Configurable](serviceARetrier)
+			Expect(ok).To(BeFalse())
+
+			var buf bytes.Buffer
+			runner := services.NewRunner(services.WithConfigDump(&buf))
+			Expect(runner.Run(ctx, serviceARetrier)).To(Succeed())
+			Expect(buf.String()).To(BeEmpty())
+			report := runner.Health(ctx)
+			Expect(report.Services).To(HaveLen(1))
+			Expect(report.Services[0].Status).To(Equal(services.HealthStatusUnhealthy))
+			Expect(report.Services[0].LastError).To(Equal(wantErr))
+			Expect(runner.Reload(ctx)).To(Succeed())
+			Expect(runner.Finish(ctx)).To(Succeed())
+
+			dumper, ok := services.As[services.ConfigDumper](serviceARetrier)
+			Expect(ok).To(BeTrue())
+			Expect(dumper.DumpConfig()).To(Equal("config"))
+		})
+
+		It("should let the Runner use the optional interfaces of the wrapped Server", func() {
+			ctrl := createController()
+			defer ctrl.Finish()
+
+			ctx, cancelFunc := context.WithCancel(context.TODO())
+			defer cancelFunc()
+
+			serverA := &struct {
+				*readyServer
+				*MockConfigurable
+				*configDumper
+			}{
+				readyServer:      newReadyServer(ctrl, "Server A"),
+				MockConfigurable: NewMockConfigurable(ctrl),
+				configDumper:     &configDumper{config: "config"},
+			}
+			closed := make(chan struct{})
+			gomock.InOrder(
+				serverA.MockConfigurable.EXPECT().Load(gomock.Any()),
+				serverA.MockServer.EXPECT().Listen(gomock.Any()).Do(func(context.Context) {
+					defer GinkgoRecover()
+					time.Sleep(time.Millisecond * 50)
+					close(serverA.ready)
+					<-closed
+				}),
+			)
+			serverA.MockServer.EXPECT().Close(gomock.Any()).Do(func(context.Context) {
+				close(closed)
+			})
+
+			serverARetrier := services.Retrier().BuildServer(serverA)
+			_, ok := serverARetrier.(services.Configurable)
+			Expect(ok).To(BeTrue())
+
+			var buf bytes.Buffer
+			var runner *services.Runner
+			serviceB := NewMockResource(ctrl)
+			serviceB.EXPECT().Start(gomock.Any()).Do(func(context.Context) {
+				defer GinkgoRecover()
+				// The Runner waited the wrapped server to be ready.
+				Expect(currentState(runner, "Server A")).To(Equal(services.StateRunning))
+				cancelFunc()
+			})
+			runner = services.NewRunner(services.WithConfigDump(&buf))
+			Expect(runner.Run(ctx, serverARetrier, serviceB)).To(MatchError(context.Canceled))
+			Expect(buf.String()).To(Equal(`{"service":"Server A","config":"config"}` + "\n"))
+		})
+
+		It("should not find the optional interfaces that the wrapped service does not implement", func() {
+			ctrl := createController()
+			defer ctrl.Finish()
+
+			serviceARetrier := services.Retrier().Build(NewMockResource(ctrl))
+			_, ok := services.As[services.HealthChecker](serviceARetrier)
+			Expect(ok).To(BeFalse())
+			serverARetrier := services.Retrier().BuildServer(NewMockServer(ctrl))
+			_, ok = services.As[services.ReadyNotifier](serverARetrier)
+			Expect(ok).To(BeFalse())
+		})
 	})
 })
+
+func TestVerifyServerServiceRetrier(t *testing.T) {
+	servicestest.VerifyServer(t, func() services.Server {
+		return services.Retrier().BuildServer(servicestest.NewServer("Server"))
+	})
+}
+
+// delayReporter is a partial services.AttemptReporter that keeps the delays of the retries of each service.
+type delayReporter struct {
+	services.NopReporter
+	mutex  sync.Mutex
+	delays map[services.Service][]time.Duration
+}
+
+func (reporter *delayReporter) AfterRetry(_ context.Context, service services.Service, _ int, _ error, delay time.Duration) {
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+	reporter.delays[service] = append(reporter.delays[service], delay)
+}
+
+// configDumper is a partial services.ConfigDumper.
+type configDumper struct {
+	config interface{}
+}
+
+func (dumper *configDumper) DumpConfig() interface{} {
+	return dumper.config
+}
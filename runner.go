@@ -2,29 +2,28 @@ package services
 
 import (
 	"context"
+	"errors"
+	"io"
 	"os"
 	"strings"
 	"sync"
+	"time"
 
 	signals "github.com/jamillosantos/go-os-signals"
 )
 
-type listenState int
-
-const (
-	ListenStateIdle listenState = iota
-	ListenStateStarting
-	ListenStateListening
-	ListenStateClosing
-	ListenStateClosed
-)
-
+// MultiErrors aggregates many errors. Nil entries are ignored.
+//
+// It supports `errors.Is` and `errors.As`, matching any of the aggregated errors.
 type MultiErrors []error
 
 func (errs MultiErrors) Error() string {
 	var r strings.Builder
-	for idx, err := range errs {
-		if idx > 0 {
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if r.Len() > 0 {
 			r.WriteString(", ")
 		}
 		r.WriteString(err.Error())
@@ -32,21 +31,64 @@ func (errs MultiErrors) Error() string {
 	return r.String()
 }
 
+// Is implements the `errors.Is` support, checking all aggregated errors.
+func (errs MultiErrors) Is(target error) bool {
+	for _, err := range errs {
+		if err != nil && errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As implements the `errors.As` support, checking all aggregated errors. The first error that matches is used.
+func (errs MultiErrors) As(target interface{}) bool {
+	for _, err := range errs {
+		if err != nil && errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
 type Runner struct {
 	startListenerOnce sync.Once
 
+	mutex            sync.Mutex
 	resourceServices []Resource
-
-	reporter        Reporter
-	listenerBuilder func() signals.Listener
+	serverServices   []Server
+	starting         int
+	ready            bool
+	lastHealthyAt    map[Service]time.Time
+	states           map[Service]*serviceState
+	stateOrder       []Service
+	reloadListener   signals.Listener
+	reloadMutex      sync.Mutex
+	configDumpMutex  sync.Mutex
+
+	reporter               Reporter
+	events                 *eventBroker
+	listenerBuilder        func() signals.Listener
+	reloadListenerBuilder  func() signals.Listener
+	configDump             io.Writer
+	shutdownTimeoutDefault time.Duration
+	startupTimeout         time.Duration
+	shutdownPhases         []shutdownPhase
+	finishFailFast         bool
+	secondSignal           func(os.Signal)
 }
 
 type StarterOption = func(*Runner)
 
-// WithReporter is a StarterOption that will set the signal listener instance of a Runner.
+// WithReporter is a StarterOption that will add a reporter to a Runner. When it is given more than once, the events
+// are forwarded to all the reporters, in order (check NewMultiReporter).
 func WithReporter(reporter Reporter) StarterOption {
 	return func(manager *Runner) {
-		manager.reporter = reporter
+		if manager.reporter == nil {
+			manager.reporter = reporter
+			return
+		}
+		manager.reporter = NewMultiReporter(manager.reporter, reporter)
 	}
 }
 
@@ -66,34 +108,176 @@ func WithSignals(ss ...os.Signal) StarterOption {
 	}
 }
 
+// WithSecondSignal is a StarterOption that sets what happens when a signal is received while Run is already gracefully
+// shutting down the servers. Use ForceExit to terminate the process immediately.
+func WithSecondSignal(handler func(os.Signal)) StarterOption {
+	return func(manager *Runner) {
+		manager.secondSignal = handler
+	}
+}
+
+// WithShutdownTimeout is a StarterOption that sets how long the Runner waits for each service to stop (Server.Close or
+// Resource.Stop). When exceeded, the Runner reports a StopTimeoutError through the Reporter and moves on to the next
+// service. Services can define their own timeout by implementing ShutdownTimeouter.
+func WithShutdownTimeout(timeout time.Duration) StarterOption {
+	return func(manager *Runner) {
+		manager.shutdownTimeoutDefault = timeout
+	}
+}
+
+// WithStartupTimeout is a StarterOption that sets how long the Runner waits for each Server that implements
+// ReadyNotifier to become ready. When exceeded, Run fails with a StartupTimeoutError. By default, the Runner waits as
+// long as needed.
+func WithStartupTimeout(timeout time.Duration) StarterOption {
+	return func(manager *Runner) {
+		manager.startupTimeout = timeout
+	}
+}
+
+// WithShutdownPhase is a StarterOption that assigns the given Server instances to a named shutdown phase. When Run
+// exits, the phases are executed in the order they were defined: all servers of a phase are closed in parallel, and
+// the next phase only starts after all of them are closed. If a PhaseReporter is used, it is notified at the
+// boundaries of each phase.
+//
+// Servers that are not assigned to any phase belong to the DefaultShutdownPhase. Unless it is explicitly defined, it
+// is executed after all other phases.
+func WithShutdownPhase(name string, servers ...Server) StarterOption {
+	return func(manager *Runner) {
+		manager.shutdownPhases = append(manager.shutdownPhases, shutdownPhase{
+			name:    name,
+			servers: servers,
+		})
+	}
+}
+
+// WithFinishFailFast is a StarterOption that makes Runner.Finish return on the first Resource that fails to stop,
+// leaving the remaining resources started. By default, Finish tries to stop all of them.
+func WithFinishFailFast() StarterOption {
+	return func(manager *Runner) {
+		manager.finishFailFast = true
+	}
+}
+
 // NewRunner creates a new instance of Runner.
 //
 // If a listener is not defined, it will create one based on DefaultSignals.
 func NewRunner(opts ...StarterOption) *Runner {
 	manager := &Runner{
 		resourceServices: make([]Resource, 0),
+		serverServices:   make([]Server, 0),
+		lastHealthyAt:    make(map[Service]time.Time),
+		states:           make(map[Service]*serviceState),
+		stateOrder:       make([]Service, 0),
+		events:           newEventBroker(),
 	}
 	for _, opt := range opts {
 		opt(manager)
 	}
+	manager.reporter = NewMultiReporter(manager.reporter, manager.events)
 
 	return manager
 }
 
-func stopServers(ctx context.Context, reporter Reporter, servers []Server) {
-	for _, server := range servers {
-		err := server.Close(ctx)
-		if reporter != nil {
-			reporter.AfterStop(ctx, server, err)
+// stopServers closes the given servers, waiting for their Listen to return (the dones are closed when that happens,
+// and the cancels cancel the ctx given to each Listen). The servers are closed phase by phase (check
+// WithShutdownPhase), and all servers of the same phase are closed in parallel. The errors of servers that did not stop
+// within their shutdown timeout are returned.
+func (r *Runner) stopServers(ctx context.Context, servers []Server, dones []chan struct{}, cancels []context.CancelFunc) error {
+	phaseReporter, hasPhaseReporter := r.reporter.(PhaseReporter)
+
+	errs := make([]error, 0)
+	for _, phase := range r.serverShutdownPhases(servers) {
+		if hasPhaseReporter {
+			phaseReporter.BeforeShutdownPhase(ctx, phase.name)
+		}
+
+		phaseErrs := make([]error, len(phase.servers))
+		var wg sync.WaitGroup
+		wg.Add(len(phase.servers))
+		for i, idx := range phase.servers {
+			go func(i, idx int) {
+				defer wg.Done()
+				phaseErrs[i] = r.stopServer(ctx, servers[idx], dones[idx], cancels[idx])
+			}(i, idx)
 		}
+		wg.Wait()
+
+		failures := make([]error, 0)
+		for _, err := range phaseErrs {
+			if err == nil {
+				continue
+			}
+			failures = append(failures, err)
+			if _, ok := err.(*StopTimeoutError); ok {
+				errs = append(errs, err)
+			}
+		}
+
+		if hasPhaseReporter {
+			phaseReporter.AfterShutdownPhase(ctx, phase.name, joinErrors(failures))
+		}
+	}
+	return joinErrors(errs)
+}
+
+// stopServer closes the given server and waits its Listen to return (the done is closed when that happens). After
+// Close returns, the ctx given to the Listen is cancelled (by cancelListen): a server that was not listening yet
+// ignores the Close, so its Listen is only interrupted by the ctx.
+//
+// When a shutdown timeout is defined, the server is closed with a ctx that is not cancelled with the given one. So, it
+// has a chance to stop gracefully even when the Run was cancelled.
+func (r *Runner) stopServer(ctx context.Context, server Server, done chan struct{}, cancelListen context.CancelFunc) error {
+	// A server whose Listen already returned stays stopped (or failed).
+	stopping := r.transitionFrom(server, StateRunning, StateStopping, nil)
+	if r.reporter != nil {
+		r.reporter.BeforeStop(ctx, server)
+	}
+
+	timeout := r.shutdownTimeout(server)
+	closeCtx := ctx
+	if timeout > 0 {
+		closeCtx = context.WithoutCancel(ctx)
+	}
+	err := stopWithTimeout(closeCtx, server, timeout, func(ctx context.Context) error {
+		err := recoverPanic(ctx, r.reporter, server, "close", func() error {
+			return server.Close(ctx)
+		})
+		cancelListen()
+		<-done
+		return err
+	})
+	if stopping {
+		r.stopped(server, err)
 	}
+	if r.reporter != nil {
+		r.reporter.AfterStop(ctx, server, err)
+	}
+	return err
+}
+
+// stopped moves the given service to stopped, or to failed when err is not nil.
+func (r *Runner) stopped(service Service, err error) {
+	if err != nil {
+		_ = r.transition(service, StateFailed, err)
+		return
+	}
+	_ = r.transition(service, StateStopped, nil)
 }
 
 // Run goes through all given Service instances trying to start them. This function only supports Resource or Server
 // instances (subset of Service). Then, it goes through all of them starting each one.
 //
 // Resource instances are initialized by calling Resource.Start, respecting the given order, only one at a time. If only
-// Resource instances are passed, this function will not block and Run can be called many times (not thread-safe).
+// Resource instances are passed, this function will not block and Run can be called many times.
+//
+// Run and Finish are safe to be called from many goroutines at the same time. Concurrent Run calls start their
+// services independently, and the resources are kept in the order they finished starting. A Finish only stops the
+// resources that finished starting before it was called.
+//
+// If any of the given services declares dependencies (check Dependent), the given order is replaced by the dependency
+// graph: services are started level by level, and all services of the same level are started in parallel. In that
+// case, the Reporter might be called from many goroutines at the same time. When the dependencies form a cycle,
+// ErrDependencyCycle is returned and no service is started.
 //
 // Server instances are initialized by invoking a new goroutine that calls the Server.Listen. So, the order is not be
 // guaranteed and all Server starts at once. Then, Run blocks until all server are closed and it can happen in two
@@ -101,6 +285,22 @@ func stopServers(ctx context.Context, reporter Reporter, servers []Server) {
 // the given ctx is cancelled. Either cases the Run will gracefully stop all Server instances that were initialized
 // (by calling Server.Close).
 //
+// A Server that implements ReadyNotifier is only considered running when it is ready: Run waits for it (check
+// WithStartupTimeout) before starting the services that depend on it and reports Reporter.AfterStart after that. If
+// its Listen returns before it is ready, Run fails with ErrExitedBeforeReady.
+//
+// When a signal is received, the Reporter is notified (check Reporter.SignalReceived) and Run returns a SignalError
+// carrying the signal (matching ErrStoppedBySignal). If the signal is received while starting the services, the error
+// also matches ErrStartCancelledBySignal. Signals received while the servers are being closed are given to the handler
+// defined by WithSecondSignal.
+//
+// The Runner tracks the lifecycle state of each service (check Runner.State and Runner.Services). A service that is
+// already running, or being started, cannot be given to Run again: a StateTransitionError is returned instead. The same
+// happens to a Resource that failed to stop, until a Finish stops it.
+//
+// Panics of the services (in Configurable.Load, Resource.Start, Server.Listen or Server.Close) are recovered and
+// handled as if the service had failed with a PanicError (check PanicReporter for being notified).
+//
 // Important: Resource instances will not be stopped when the a os.Signal is received or the ctx is cancelled. For that,
 // you should call Runner.Finish.
 //
@@ -109,6 +309,37 @@ func stopServers(ctx context.Context, reporter Reporter, servers []Server) {
 // Whenever this function exists, all given Server instances will be closed by using Server.Close. Then, it will wait
 // until the Server.Listen finished.
 func (r *Runner) Run(ctx context.Context, services ...Service) (errResult error) {
+	if runReporter, ok := r.reporter.(RunReporter); ok {
+		ctx = runReporter.BeforeRun(ctx, services)
+		defer func() {
+			runReporter.AfterRun(ctx, errResult)
+		}()
+	}
+
+	for _, service := range services {
+		if err := checkComparable(service); err != nil {
+			return err
+		}
+	}
+
+	r.mutex.Lock()
+	levels, err := dependencyLevels(services, r.resourceServices)
+	if err == nil {
+		err = r.checkNotHeld(services)
+	}
+	if err == nil {
+		r.registerServices(services)
+	}
+	r.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	r.startReloading()
+
+	doneStarting := r.beginStarting()
+	defer doneStarting(false)
+
 	var listener signals.Listener
 	if r.listenerBuilder == nil {
 		listener = signals.NewListener(DefaultSignals...)
@@ -120,79 +351,105 @@ func (r *Runner) Run(ctx context.Context, services ...Service) (errResult error)
 	ctxSignal, cancelFunc := context.WithCancel(context.Background())
 	defer cancelFunc()
 
-	go func() {
-		// Intercepts a signal cancelling the procedure of starting services.
-		<-listener.Receive()
-		cancelFunc()
-	}()
+	// Intercepts a signal cancelling the procedure of starting services.
+	var watcher signalWatcher
+	go watcher.watch(listener, r.reporter, cancelFunc, r.secondSignal)
+
+	// The ctx given to Configurable.Load and Resource.Start is also cancelled by a signal received while starting, so a
+	// long start (or a retrier) can be interrupted. It is released when Run returns.
+	ctxStart, cancelStart := context.WithCancel(ctx)
+	defer cancelStart()
+	stopCancellingStart := context.AfterFunc(ctxSignal, cancelStart)
+	defer stopCancellingStart()
 
 	hasReporter := r.reporter != nil
 
 	servers := make([]Server, 0, len(services))
+	serversDone := make([]chan struct{}, 0, len(services))
+	serversCancel := make([]context.CancelFunc, 0, len(services))
 	hasServer := false
 	var serversMutex sync.Mutex
 
-	var wgServers sync.WaitGroup
-
-	// Make sure that all servers will be finished
-	defer wgServers.Wait()
+	// errs receives the failures of the servers. It is never closed, since the servers closed by the shutdown can still
+	// fail. Each server sends at most once, so sending never blocks.
+	errs := make(chan errPair, len(services))
+	// serverErrs is the returned error when Run exits because a server failed.
+	var serverErrs MultiErrors
 
-	// Finish all servers
+	// Finish all servers, making sure that all of them are finished.
 	defer func() {
+		watcher.beginShutdown()
+
 		serversMutex.Lock()
 		defer serversMutex.Unlock()
-		stopServers(ctx, r.reporter, servers)
+		if len(servers) > 0 {
+			r.removeServers(servers)
+		}
+		err := r.stopServers(ctx, servers, serversDone, serversCancel)
+		if serverErrs != nil {
+			// All Listen calls returned, so the failures of the servers closed by the shutdown are already sent. Since
+			// errResult is serverErrs, they are added to it.
+			drainServerErrors(errs, serverErrs)
+		}
+		if err == nil {
+			return
+		}
+		var signalErr *SignalError
+		switch {
+		case errResult == nil:
+			errResult = err
+		case errors.As(errResult, &signalErr):
+			// Stopping by a signal is not a failure, so the shutdown errors are kept along with it.
+			errResult = MultiErrors{errResult, err}
+		}
 	}()
 
-	errs := make(chan errPair, len(services))
-
-	// Go through all resourceServices starting one by one.
-	serverCount := 0
-	for _, service := range services {
-		// Check if the starting process was cancelled.
+	// cancelled checks if the starting process was cancelled. Once a Server is started, the starting process is not
+	// interrupted anymore, the servers will be closed when Run exits.
+	cancelled := func() error {
+		if hasServer {
+			return nil
+		}
 		select {
 		case <-ctx.Done():
-			if hasServer {
-				break
-			}
 			return ctx.Err()
 		case <-ctxSignal.Done():
-			if hasServer {
-				break
-			}
-			return ErrStartCancelledBySignal
+			return watcher.err(true)
 		default:
 			// Not cancelled ...
+			return nil
 		}
+	}
 
+	startService := func(service Service) error {
 		// If the service is configurable
-		if srv, ok := service.(Configurable); ok {
+		if srv, ok := As[Configurable](service); ok {
+			if err := r.transition(service, StateLoading, nil); err != nil {
+				return err
+			}
 			if hasReporter {
 				r.reporter.BeforeLoad(ctx, srv)
 			}
-			errResult = srv.Load(ctx)
+			err := recoverPanic(ctx, r.reporter, service, "load", func() error {
+				return srv.Load(ctxStart)
+			})
 			if hasReporter {
-				r.reporter.AfterLoad(ctx, srv, errResult)
+				r.reporter.AfterLoad(ctx, srv, err)
 			}
-			if errResult != nil {
-				return
+			if err != nil {
+				_ = r.transition(service, StateFailed, err)
+				return err
 			}
+			r.dumpConfig(service)
 		}
 
 		// Loading configuration can take a long time. Then, check if the starting process was cancelled again.
-		select {
-		case <-ctx.Done():
-			if hasServer {
-				break
-			}
-			return ctx.Err()
-		case <-ctxSignal.Done():
-			if hasServer {
-				break
-			}
-			return ErrStartCancelledBySignal
-		default:
-			// Not cancelled ...
+		if err := cancelled(); err != nil {
+			return err
+		}
+
+		if err := r.transition(service, StateStarting, nil); err != nil {
+			return err
 		}
 
 		if hasReporter {
@@ -201,106 +458,329 @@ func (r *Runner) Run(ctx context.Context, services ...Service) (errResult error)
 
 		switch s := service.(type) {
 		case Resource:
-			errResult = s.Start(ctx)
-			if hasReporter {
-				r.reporter.AfterStart(ctx, service, errResult)
+			err := recoverPanic(ctx, r.reporter, service, "start", func() error {
+				return s.Start(ctxStart)
+			})
+			if err != nil {
+				_ = r.transition(service, StateFailed, err)
+			} else {
+				_ = r.transition(service, StateRunning, nil)
 			}
-			if errResult != nil {
-				return
+			if hasReporter {
+				r.reporter.AfterStart(ctx, service, err)
 			}
-			r.resourceServices = append(r.resourceServices, s)
+			return err
 		case Server:
-			hasServer = true
-			wgServers.Add(1)
+			done := make(chan struct{})
+			// Each Listen has its own ctx, so it can be interrupted when the server does not become ready.
+			listenCtx, cancelListen := context.WithCancel(ctx)
 
 			serversMutex.Lock()
+			idx := len(servers)
 			servers = append(servers, s)
+			serversDone = append(serversDone, done)
+			serversCancel = append(serversCancel, cancelListen)
 			serversMutex.Unlock()
 
+			r.mutex.Lock()
+			r.serverServices = append(r.serverServices, s)
+			r.mutex.Unlock()
+
+			// A server that notifies its readiness is only running when it is ready.
+			notifier, waitReady := As[ReadyNotifier](s)
+			if !waitReady {
+				_ = r.transition(s, StateRunning, nil)
+			}
+
+			var listenErr error
 			go func(s Server, idx int) {
-				defer wgServers.Done()
+				defer close(done)
 
-				err := s.Listen(ctx)
+				err := recoverPanic(ctx, r.reporter, s, "listen", func() error {
+					return s.Listen(listenCtx)
+				})
+				listenErr = err
+				r.listenReturned(s, err)
 				if err != nil && err != context.Canceled {
 					errs <- errPair{
 						idx,
 						err,
 					}
 				}
-			}(s, serverCount)
-			serverCount++
+			}(s, idx)
+
+			if !waitReady {
+				return nil
+			}
+			err := r.waitReady(ctxStart, s, notifier, done, func() error {
+				return listenErr
+			})
+			if err != nil {
+				cancelListen()
+				_ = r.transition(s, StateFailed, err)
+			} else {
+				_ = r.transition(s, StateRunning, nil)
+				select {
+				case <-done:
+					// The Listen returned before the server was set as running.
+					r.listenReturned(s, listenErr)
+				default:
+				}
+			}
+			if hasReporter {
+				r.reporter.AfterStart(ctx, service, err)
+			}
+			return err
 		}
+		return nil
 	}
 
-	// Loading configuration can take a long time. Then, check if the starting process was cancelled again.
-	select {
-	case <-ctx.Done():
-		if !hasServer {
-			return ctx.Err()
+	// Go through all levels starting the services of each level at once.
+	for _, level := range levels {
+		// Check if the starting process was cancelled.
+		if err := cancelled(); err != nil {
+			return err
+		}
+
+		levelErrs := startLevel(level, startService)
+
+		for idx, service := range level {
+			switch s := service.(type) {
+			case Resource:
+				if levelErrs[idx] == nil {
+					r.mutex.Lock()
+					r.resourceServices = append(r.resourceServices, s)
+					r.mutex.Unlock()
+				}
+			case Server:
+				// A server that failed to become ready is not considered started.
+				if levelErrs[idx] == nil {
+					hasServer = true
+				}
+			}
 		}
-	case <-ctxSignal.Done():
-		if !hasServer {
-			return ErrStartCancelledBySignal
+
+		for _, err := range levelErrs {
+			if err != nil {
+				// A service might have failed because it was interrupted by a signal.
+				if cancelErr := cancelled(); cancelErr != nil {
+					return cancelErr
+				}
+				return err
+			}
 		}
-	default:
-		// Not cancelled ...
 	}
 
+	// Loading configuration can take a long time. Then, check if the starting process was cancelled again.
+	if err := cancelled(); err != nil {
+		return err
+	}
+
+	stopCancellingStart()
+	doneStarting(true)
+
 	if !hasServer {
 		return nil
 	}
 
-	errMulti := make(MultiErrors, serverCount)
+	serversMutex.Lock()
+	errMulti := make(MultiErrors, len(servers))
+	serversMutex.Unlock()
 
 	select {
 	case ep := <-errs:
-		if ep.err != nil {
-			errMulti[ep.idx] = ep.err
-		}
-		close(errs)
-		for ep := range errs {
-			errMulti[ep.idx] = ep.err
-		}
+		errMulti[ep.idx] = ep.err
+		drainServerErrors(errs, errMulti)
+		serverErrs = errMulti
 		return errMulti
 	case <-ctxSignal.Done(): // Wait a signal to come in.
-		return nil
+		return watcher.err(false)
 	case <-ctx.Done(): // the deferred methods will handle this...
 		return ctx.Err()
 	}
 }
 
-// Finish will go through all started resourceServices, in the opposite order they were started, stopping one by one. If any,
-// failure is detected, the function will stop leaving some started resourceServices.
+// beginStarting marks the Runner as not ready until the returned function is called. The Runner becomes ready again
+// when all Run calls have successfully finished starting their services.
+func (r *Runner) beginStarting() func(succeeded bool) {
+	r.mutex.Lock()
+	r.starting++
+	r.ready = false
+	r.mutex.Unlock()
+
+	var once sync.Once
+	return func(succeeded bool) {
+		once.Do(func() {
+			r.mutex.Lock()
+			defer r.mutex.Unlock()
+			r.starting--
+			r.ready = succeeded && r.starting == 0
+		})
+	}
+}
+
+// removeServers removes the given servers from the list of listening servers, marking the Runner as not ready.
+func (r *Runner) removeServers(servers []Server) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.ready = false
+	serverServices := make([]Server, 0, len(r.serverServices))
+	for _, server := range r.serverServices {
+		if !containsServer(servers, server) {
+			serverServices = append(serverServices, server)
+		}
+	}
+	r.serverServices = serverServices
+}
+
+func containsServer(servers []Server, server Server) bool {
+	for _, s := range servers {
+		if s == server {
+			return true
+		}
+	}
+	return false
+}
+
+// startLevel starts all the given services in parallel, waiting all of them to finish. The returned errors are in the
+// same order as the services.
+func startLevel(level []Service, start func(Service) error) []error {
+	errs := make([]error, len(level))
+	if len(level) == 1 {
+		errs[0] = start(level[0])
+		return errs
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(len(level))
+	for idx, service := range level {
+		go func(idx int, service Service) {
+			defer wg.Done()
+			errs[idx] = start(service)
+		}(idx, service)
+	}
+	wg.Wait()
+	return errs
+}
+
+// Finish will go through all started resourceServices, in the opposite order they were started, stopping one by one.
+// If a Resource fails to stop, Finish moves on to the next one and, in the end, returns all failures aggregated in a
+// MultiErrors (or the error itself, when there is only one). Each failure is a StopError identifying the Resource,
+// and the failed resources are kept, so a later call to Finish will retry stopping only them. If WithFinishFailFast is
+// used, the function will stop on the first failure leaving the remaining started resourceServices.
+//
+// Finish also stops listening the reload signals (check WithReloadSignals).
+//
+// A panic in Resource.Stop is recovered and handled as a failure with a PanicError.
+//
+// If a resource does not stop within its shutdown timeout (check WithShutdownTimeout), it is left behind and Finish
+// moves on to the next one. In that case, the returned error includes a StopTimeoutError for each of them.
 func (r *Runner) Finish(ctx context.Context) (errResult error) {
+	if runReporter, ok := r.reporter.(RunReporter); ok {
+		ctx = runReporter.BeforeFinish(ctx)
+		defer func() {
+			runReporter.AfterFinish(ctx, errResult)
+		}()
+	}
+
 	ctx, cancelFunc := context.WithCancel(ctx)
 	defer cancelFunc()
 
 	hasReporter := r.reporter != nil
 
-	for i := len(r.resourceServices) - 1; i >= 0; i-- {
-		service := r.resourceServices[i]
+	r.stopReloading()
+
+	// The resources are taken from the Runner, so concurrent Finish calls do not stop the same resources.
+	r.mutex.Lock()
+	r.ready = false
+	resources := r.resourceServices
+	r.resourceServices = make([]Resource, 0)
+	r.mutex.Unlock()
+
+	errs := make([]error, 0)
+	// failed holds the resources that will be kept, in the reverse order they were started.
+	failed := make([]Resource, 0)
+	for i := len(resources) - 1; i >= 0; i-- {
+		service := resources[i]
+		_ = r.transition(service, StateStopping, nil)
 		if hasReporter {
 			r.reporter.BeforeStop(ctx, service)
 		}
-		err := service.Stop(ctx)
+		err := stopWithTimeout(ctx, service, r.shutdownTimeout(service), func(ctx context.Context) error {
+			return recoverPanic(ctx, r.reporter, service, "stop", func() error {
+				return service.Stop(ctx)
+			})
+		})
+		r.stopped(service, err)
 		if hasReporter {
 			r.reporter.AfterStop(ctx, service, err)
 		}
-		if err != nil {
-			return err
+		if err == nil {
+			continue
 		}
-		r.resourceServices = r.resourceServices[:len(r.resourceServices)-1]
+		if _, ok := err.(*StopTimeoutError); ok {
+			errs = append(errs, err)
+			continue
+		}
+		errs = append(errs, &StopError{
+			Service: service,
+			Err:     err,
+		})
+		if r.finishFailFast {
+			for j := i; j >= 0; j-- {
+				failed = append(failed, resources[j])
+			}
+			break
+		}
+		failed = append(failed, service)
+	}
+
+	r.mutex.Lock()
+	remaining := make([]Resource, 0, len(failed)+len(r.resourceServices))
+	for i := len(failed) - 1; i >= 0; i-- {
+		remaining = append(remaining, failed[i])
 	}
-	return nil
+	// Resources started while finishing are kept.
+	r.resourceServices = append(remaining, r.resourceServices...)
+	r.mutex.Unlock()
+
+	return joinErrors(errs)
 }
 
-// WithReporter sets the reporter for this Runner instance, returning it afterwards.
+// WithReporter sets the reporter for this Runner instance, replacing the reporters given by the WithReporter option,
+// returning it afterwards. Use NewMultiReporter to set more than one reporter. It is not safe to be called while Run,
+// or Finish, is executing.
 func (r *Runner) WithReporter(reporter Reporter) *Runner {
-	r.reporter = reporter
+	r.reporter = NewMultiReporter(reporter, r.events)
 	return r
 }
 
+// Events subscribes to the lifecycle events of the services run by this Runner: loaded (EventAfterLoad), starting
+// (EventBeforeStart), started or failed (EventAfterStart, failed when the Err is set), retrying (EventBeforeRetry and
+// EventAfterRetry, published by the Retrier instances run by this Runner), stopping (EventBeforeStop), stopped
+// (EventAfterStop) and signal received (EventSignalReceived), among the others reported to a Reporter. Each Event has
+// the time it happened.
+//
+// Publishing never blocks the Runner: when the Subscription buffer is full, the new events are dropped (check
+// Subscription.Dropped). Call Subscription.Close when done.
+func (r *Runner) Events(opts ...SubscriptionOption) *Subscription {
+	return r.events.subscribe(opts)
+}
+
 type errPair struct {
 	idx int
 	err error
 }
+
+// drainServerErrors moves the errors already sent to errs into errMulti, without blocking.
+func drainServerErrors(errs <-chan errPair, errMulti MultiErrors) {
+	for {
+		select {
+		case ep := <-errs:
+			errMulti[ep.idx] = ep.err
+		default:
+			return
+		}
+	}
+}
@@ -3,6 +3,7 @@ package services_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"time"
 
@@ -19,6 +20,20 @@ func createController() *gomock.Controller {
 	return gomock.NewController(GinkgoT(1))
 }
 
+// ctxWithValue is a gomock.Matcher of the contexts that have a value for the key.
+type ctxWithValue struct {
+	key interface{}
+}
+
+func (m ctxWithValue) Matches(x interface{}) bool {
+	ctx, ok := x.(context.Context)
+	return ok && ctx.Value(m.key) != nil
+}
+
+func (m ctxWithValue) String() string {
+	return fmt.Sprintf("is a context with a value for %T", m.key)
+}
+
 var _ = Describe("Runner", func() {
 	Describe("Run Resource instances", func() {
 		It("should start Resouce instances", func() {
@@ -44,6 +59,24 @@ var _ = Describe("Runner", func() {
 			Expect(runner.Run(ctx, serviceA, serviceB, serviceC)).To(Succeed())
 		})
 
+		It("should release the ctx given to Start when Run returns", func() {
+			ctrl := createController()
+			defer ctrl.Finish()
+
+			ctx, cancelFunc := context.WithCancel(context.TODO())
+			defer cancelFunc()
+
+			var startCtx context.Context
+			serviceA := NewMockResource(ctrl)
+			serviceA.EXPECT().Start(gomock.Any()).Do(func(ctx context.Context) {
+				startCtx = ctx
+			})
+
+			Expect(services.NewRunner().Run(ctx, serviceA)).To(Succeed())
+			Expect(startCtx.Err()).To(MatchError(context.Canceled))
+			Expect(ctx.Err()).ToNot(HaveOccurred())
+		})
+
 		It("should not stop started Resource instances when one fails", func() {
 			ctrl := createController()
 			defer ctrl.Finish()
@@ -199,6 +232,81 @@ var _ = Describe("Runner", func() {
 			// 3. Close the resourceServices and ensure an error was returned.
 			Expect(runner.Finish(ctx)).To(MatchError(errA))
 		})
+
+		It("should keep stopping the remaining resourceServices when one fails", func() {
+			ctrl := createController()
+			defer ctrl.Finish()
+
+			ctx := context.TODO()
+
+			// 1. Create 3 resourceServices
+			serviceA := NewMockResource(ctrl)
+			serviceB := NewMockResource(ctrl)
+			serviceC := NewMockResource(ctrl)
+
+			errB := errors.New("error B")
+			errC := errors.New("error C")
+			gomock.InOrder(
+				serviceA.EXPECT().Start(gomock.Any()),
+				serviceB.EXPECT().Start(gomock.Any()),
+				serviceC.EXPECT().Start(gomock.Any()),
+				serviceC.EXPECT().Stop(gomock.Any()).Return(errC),
+				serviceB.EXPECT().Stop(gomock.Any()).Return(errB),
+				serviceA.EXPECT().Stop(gomock.Any()),
+				// Second Finish only retries the failed ones.
+				serviceC.EXPECT().Stop(gomock.Any()),
+				serviceB.EXPECT().Stop(gomock.Any()),
+			)
+
+			// 2. Triggers the Runner
+			runner := services.NewRunner()
+			Expect(runner.Run(ctx, serviceA, serviceB, serviceC)).To(Succeed())
+
+			// 3. Close the resourceServices and ensure all errors were returned.
+			err := runner.Finish(ctx)
+			Expect(err).To(MatchError(errB))
+			Expect(err).To(MatchError(errC))
+
+			var stopErr *services.StopError
+			Expect(errors.As(err, &stopErr)).To(BeTrue())
+			Expect(stopErr.Service).To(Equal(serviceC))
+			Expect(err.(services.MultiErrors)).To(HaveLen(2))
+
+			// 4. Retry finishing.
+			Expect(runner.Finish(ctx)).To(Succeed())
+		})
+
+		It("should stop on the first failure when using WithFinishFailFast", func() {
+			ctrl := createController()
+			defer ctrl.Finish()
+
+			ctx := context.TODO()
+
+			// 1. Create 3 resourceServices
+			serviceA := NewMockResource(ctrl)
+			serviceB := NewMockResource(ctrl)
+			serviceC := NewMockResource(ctrl)
+
+			errB := errors.New("error B")
+			gomock.InOrder(
+				serviceA.EXPECT().Start(gomock.Any()),
+				serviceB.EXPECT().Start(gomock.Any()),
+				serviceC.EXPECT().Start(gomock.Any()),
+				serviceC.EXPECT().Stop(gomock.Any()),
+				serviceB.EXPECT().Stop(gomock.Any()).Return(errB),
+				// Second Finish continues from the failed one.
+				serviceB.EXPECT().Stop(gomock.Any()),
+				serviceA.EXPECT().Stop(gomock.Any()),
+			)
+
+			// 2. Triggers the Runner
+			runner := services.NewRunner(services.WithFinishFailFast())
+			Expect(runner.Run(ctx, serviceA, serviceB, serviceC)).To(Succeed())
+
+			// 3. Close the resourceServices and ensure an error was returned.
+			Expect(runner.Finish(ctx)).To(MatchError(errB))
+			Expect(runner.Finish(ctx)).To(Succeed())
+		})
 	})
 
 	Describe("Run Server instances", func() {
@@ -270,6 +378,49 @@ var _ = Describe("Runner", func() {
 					nil, nil, wantErr,
 				}))
 			})
+
+			It("should collect the failures of the servers closed by the shutdown", func() {
+				ctrl := createController()
+				defer ctrl.Finish()
+
+				ctx := context.TODO()
+
+				serviceA := NewMockServer(ctrl)
+				serviceB := NewMockServer(ctrl)
+
+				errA := errors.New("listen failed")
+				errB := errors.New("close failed")
+				panicB := errors.New("panic on close")
+
+				closedB := make(chan struct{})
+				serviceA.EXPECT().Listen(gomock.Any()).Do(func(context.Context) {
+					time.Sleep(time.Millisecond * 50)
+				}).Return(errA)
+				serviceB.EXPECT().Listen(gomock.Any()).DoAndReturn(func(context.Context) error {
+					<-closedB
+					return errB
+				})
+				serviceA.EXPECT().Close(gomock.Any()).AnyTimes()
+				serviceB.EXPECT().Close(gomock.Any()).Do(func(context.Context) {
+					close(closedB)
+				})
+
+				serviceC := NewMockServer(ctrl)
+				closedC := make(chan struct{})
+				serviceC.EXPECT().Listen(gomock.Any()).Do(func(context.Context) {
+					<-closedC
+					panic(panicB)
+				})
+				serviceC.EXPECT().Close(gomock.Any()).Do(func(context.Context) {
+					close(closedC)
+				})
+
+				runner := services.NewRunner()
+				err := runner.Run(ctx, serviceA, serviceB, serviceC)
+				Expect(err).To(MatchError(errA))
+				Expect(err).To(MatchError(errB))
+				Expect(err).To(MatchError(services.ErrPanic))
+			})
 		})
 
 		When("receive a signal", func() {
@@ -306,13 +457,154 @@ var _ = Describe("Runner", func() {
 
 				go func() {
 					defer GinkgoRecover()
-					Expect(runner.Run(ctx, serverA, serverB, serverC)).To(Succeed())
+					err := runner.Run(ctx, serverA, serverB, serverC)
+					Expect(err).To(MatchError(services.ErrStoppedBySignal))
+
+					var signalErr *services.SignalError
+					Expect(errors.As(err, &signalErr)).To(BeTrue())
+					Expect(signalErr.Signal).To(Equal(os.Interrupt))
+					Expect(signalErr.ExitCode()).To(Equal(130))
 				}()
 
 				time.Sleep(time.Millisecond * 100)
 				listener.Send(os.Interrupt)
 				time.Sleep(time.Second)
 			})
+
+			It("should report the signal received", func() {
+				ctrl := createController()
+				defer ctrl.Finish()
+
+				ctx := context.TODO()
+
+				closed := make(chan struct{})
+				serverA := NewMockServer(ctrl)
+				serverA.EXPECT().Listen(gomock.Any()).Do(func(context.Context) {
+					<-closed
+				})
+
+				reporter := NewMockReporter(ctrl)
+				reporter.EXPECT().BeforeStart(gomock.Any(), serverA)
+				gomock.InOrder(
+					reporter.EXPECT().SignalReceived(os.Interrupt),
+					reporter.EXPECT().BeforeStop(gomock.Any(), serverA),
+					serverA.EXPECT().Close(gomock.Any()).Do(func(context.Context) {
+						close(closed)
+					}),
+					reporter.EXPECT().AfterStop(gomock.Any(), serverA, nil),
+				)
+
+				listener := signaltest.NewMockListener(os.Interrupt)
+				runner := services.NewRunner(services.WithReporter(reporter), services.WithListenerBuilder(func() signals.Listener {
+					return listener
+				}))
+
+				go func() {
+					time.Sleep(time.Millisecond * 50)
+					listener.Send(os.Interrupt)
+				}()
+
+				Expect(runner.Run(ctx, serverA)).To(MatchError(services.ErrStoppedBySignal))
+			})
+
+			It("should cancel starting Resource instances", func() {
+				ctrl := createController()
+				defer ctrl.Finish()
+
+				ctx := context.TODO()
+
+				serviceA := NewMockResource(ctrl)
+				serviceB := NewMockResource(ctrl)
+
+				listener := signaltest.NewMockListener(os.Interrupt)
+				serviceA.EXPECT().Start(gomock.Any()).Do(func(context.Context) {
+					listener.Send(os.Interrupt)
+				})
+
+				runner := services.NewRunner(services.WithListenerBuilder(func() signals.Listener {
+					return listener
+				}))
+
+				err := runner.Run(ctx, serviceA, serviceB)
+				Expect(err).To(MatchError(services.ErrStartCancelledBySignal))
+				Expect(err).To(MatchError(services.ErrStoppedBySignal))
+				Expect(err.Error()).To(Equal("start cancelled by signal: interrupt"))
+			})
+
+			It("should call the second signal handler when a signal is received while shutting down", func() {
+				ctrl := createController()
+				defer ctrl.Finish()
+
+				ctx := context.TODO()
+
+				listener := signaltest.NewMockListener(os.Interrupt)
+
+				closed := make(chan struct{})
+				serverA := NewMockServer(ctrl)
+				serverA.EXPECT().Listen(gomock.Any()).Do(func(context.Context) {
+					<-closed
+				})
+				secondSignal := make(chan os.Signal, 1)
+				serverA.EXPECT().Close(gomock.Any()).Do(func(context.Context) {
+					// Simulates a slow shutdown receiving a second signal.
+					listener.Send(os.Interrupt)
+					Eventually(secondSignal).Should(Receive(Equal(os.Interrupt)))
+					close(closed)
+				})
+
+				runner := services.NewRunner(
+					services.WithListenerBuilder(func() signals.Listener {
+						return listener
+					}),
+					services.WithSecondSignal(func(sig os.Signal) {
+						secondSignal <- sig
+					}),
+				)
+
+				go func() {
+					time.Sleep(time.Millisecond * 50)
+					listener.Send(os.Interrupt)
+				}()
+
+				Expect(runner.Run(ctx, serverA)).To(MatchError(services.ErrStoppedBySignal))
+			})
+		})
+	})
+
+	Describe("RunReporter", func() {
+		type ctxKey struct{}
+
+		It("should be notified around Run and Finish, giving its ctx to the services and events", func() {
+			ctrl := createController()
+			defer ctrl.Finish()
+
+			ctx := context.TODO()
+			hasValue := ctxWithValue{ctxKey{}}
+
+			serviceA := NewMockResource(ctrl)
+			wantErr := errors.New("stop failed")
+
+			reporter := NewMockRunReporter(ctrl)
+			gomock.InOrder(
+				reporter.EXPECT().BeforeRun(ctx, []services.Service{serviceA}).DoAndReturn(func(ctx context.Context, _ []services.Service) context.Context {
+					return context.WithValue(ctx, ctxKey{}, "run")
+				}),
+				reporter.EXPECT().BeforeStart(hasValue, serviceA),
+				serviceA.EXPECT().Start(hasValue),
+				reporter.EXPECT().AfterStart(hasValue, serviceA, nil),
+				reporter.EXPECT().AfterRun(hasValue, nil),
+				reporter.EXPECT().BeforeFinish(ctx).DoAndReturn(func(ctx context.Context) context.Context {
+					return context.WithValue(ctx, ctxKey{}, "finish")
+				}),
+				reporter.EXPECT().BeforeStop(hasValue, serviceA),
+				serviceA.EXPECT().Stop(hasValue).Return(wantErr),
+				reporter.EXPECT().AfterStop(hasValue, serviceA, wantErr),
+				reporter.EXPECT().AfterFinish(hasValue, gomock.Not(nil)),
+			)
+
+			runner := services.NewRunner(services.WithReporter(reporter))
+			Expect(runner.Run(ctx, serviceA)).To(Succeed())
+			Expect(runner.Finish(ctx)).To(MatchError(wantErr))
 		})
 	})
 })
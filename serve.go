@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+)
+
+const (
+	// ExitOK is the exit code for an application that was stopped without failures (check ExitCode).
+	ExitOK = 0
+	// ExitFailure is the exit code for an application that failed starting, running or stopping its services (check
+	// ExitCode).
+	ExitFailure = 1
+)
+
+// Serve runs the full lifecycle of an application: it starts the given resources, then starts the servers blocking
+// until they are closed (check Runner.Run) and, in the end, stops the resources by calling Runner.Finish. The servers
+// are not started if the resources fail to start.
+//
+// Finish is called on every path. The panics of the services are recovered by the Runner (check PanicError), any
+// other panic is propagated after the resources are stopped. Since the ctx is usually cancelled to stop the servers,
+// Finish receives a ctx that is not cancelled with it. Use WithShutdownTimeout to limit how long the resources take to
+// stop.
+//
+// The errors of Run and Finish are aggregated in a MultiErrors, when both fail. Use ExitCode to map the returned error
+// to a process exit code.
+func (r *Runner) Serve(ctx context.Context, resources []Service, servers ...Service) (errResult error) {
+	defer func() {
+		err := r.Finish(context.WithoutCancel(ctx))
+		switch {
+		case err == nil:
+		case errResult == nil:
+			errResult = err
+		default:
+			errResult = MultiErrors{errResult, err}
+		}
+	}()
+
+	if err := r.Run(ctx, resources...); err != nil {
+		return err
+	}
+	return r.Run(ctx, servers...)
+}
+
+// Main creates a Runner with the given options and calls Runner.Serve, returning the process exit code (check
+// ExitCode). When the application fails, the error is written to the standard error output. It is meant to be used as
+// the whole body of the `main` function:
+//
+//	func main() {
+//		os.Exit(services.Main(context.Background(), nil, resources, servers...))
+//	}
+func Main(ctx context.Context, opts []StarterOption, resources []Service, servers ...Service) int {
+	err := NewRunner(opts...).Serve(ctx, resources, servers...)
+	code := ExitCode(err)
+	if code == ExitFailure {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+	}
+	return code
+}
+
+// ExitCode maps the error returned by Runner.Serve, or Runner.Run, to a process exit code:
+//
+//   - ExitOK when there is no error, or the ctx was cancelled;
+//   - the conventional exit code of the signal (check SignalError.ExitCode) when it was stopped by a signal;
+//   - ExitFailure when any service failed. It takes precedence over the signal received.
+func ExitCode(err error) int {
+	code := ExitOK
+	for _, err := range flattenErrors(err) {
+		var signalErr *SignalError
+		switch {
+		case err == context.Canceled:
+		case errors.As(err, &signalErr):
+			if code == ExitOK {
+				code = signalErr.ExitCode()
+			}
+		default:
+			return ExitFailure
+		}
+	}
+	return code
+}
+
+// flattenErrors returns the errors aggregated by MultiErrors, recursively, ignoring the nil ones.
+func flattenErrors(err error) []error {
+	multi, ok := err.(MultiErrors)
+	if !ok {
+		if err == nil {
+			return nil
+		}
+		return []error{err}
+	}
+	result := make([]error, 0, len(multi))
+	for _, err := range multi {
+		result = append(result, flattenErrors(err)...)
+	}
+	return result
+}
@@ -0,0 +1,207 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+
+	"github.com/golang/mock/gomock"
+	signals "github.com/jamillosantos/go-os-signals"
+	"github.com/jamillosantos/go-os-signals/signaltest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/setare/go-services"
+)
+
+var _ = Describe("Serve", func() {
+	It("should start the resources, the servers and finish the resources", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx, cancelFunc := context.WithCancel(context.TODO())
+		defer cancelFunc()
+
+		closed := make(chan struct{})
+		resourceA := NewMockResource(ctrl)
+		resourceB := NewMockResource(ctrl)
+		serverA := NewMockServer(ctrl)
+
+		gomock.InOrder(
+			resourceA.EXPECT().Start(gomock.Any()),
+			resourceB.EXPECT().Start(gomock.Any()),
+			serverA.EXPECT().Listen(gomock.Any()).Do(func(context.Context) {
+				cancelFunc()
+				<-closed
+			}),
+			serverA.EXPECT().Close(gomock.Any()).Do(func(context.Context) {
+				close(closed)
+			}),
+			resourceB.EXPECT().Stop(gomock.Any()).Do(func(ctx context.Context) {
+				defer GinkgoRecover()
+				// The resources must stop gracefully, even though the ctx was cancelled.
+				Expect(ctx.Err()).ToNot(HaveOccurred())
+			}),
+			resourceA.EXPECT().Stop(gomock.Any()),
+		)
+
+		runner := services.NewRunner()
+		err := runner.Serve(ctx, []services.Service{resourceA, resourceB}, serverA)
+		Expect(err).To(MatchError(context.Canceled))
+		Expect(services.ExitCode(err)).To(Equal(services.ExitOK))
+	})
+
+	It("should not start the servers when a resource fails to start", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		wantErr := errors.New("random error")
+		resourceA := NewMockResource(ctrl)
+		resourceB := NewMockResource(ctrl)
+		serverA := NewMockServer(ctrl)
+
+		gomock.InOrder(
+			resourceA.EXPECT().Start(gomock.Any()),
+			resourceB.EXPECT().Start(gomock.Any()).Return(wantErr),
+			resourceA.EXPECT().Stop(gomock.Any()),
+		)
+
+		runner := services.NewRunner()
+		err := runner.Serve(ctx, []services.Service{resourceA, resourceB}, serverA)
+		Expect(err).To(MatchError(wantErr))
+		Expect(services.ExitCode(err)).To(Equal(services.ExitFailure))
+	})
+
+	It("should aggregate the errors of running and finishing", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		listenErr := errors.New("listen error")
+		stopErr := errors.New("stop error")
+		resourceA := NewMockResource(ctrl)
+		serverA := NewMockServer(ctrl)
+
+		gomock.InOrder(
+			resourceA.EXPECT().Start(gomock.Any()),
+			serverA.EXPECT().Listen(gomock.Any()).Return(listenErr),
+			serverA.EXPECT().Close(gomock.Any()),
+			resourceA.EXPECT().Stop(gomock.Any()).Return(stopErr),
+		)
+
+		runner := services.NewRunner()
+		err := runner.Serve(ctx, []services.Service{resourceA}, serverA)
+		Expect(err).To(MatchError(listenErr))
+		Expect(err).To(MatchError(stopErr))
+		Expect(services.ExitCode(err)).To(Equal(services.ExitFailure))
+	})
+
+	It("should finish the resources when a service panics", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		resourceA := NewMockResource(ctrl)
+		resourceA.EXPECT().Name().Return("Resource A").AnyTimes()
+		resourceB := NewMockResource(ctrl)
+		resourceB.EXPECT().Name().Return("Resource B").AnyTimes()
+
+		gomock.InOrder(
+			resourceA.EXPECT().Start(gomock.Any()),
+			resourceB.EXPECT().Start(gomock.Any()).Do(func(context.Context) {
+				panic("random panic")
+			}),
+			resourceA.EXPECT().Stop(gomock.Any()),
+		)
+
+		runner := services.NewRunner()
+		err := runner.Serve(ctx, []services.Service{resourceA, resourceB})
+		Expect(err).To(MatchError(services.ErrPanic))
+		Expect(services.ExitCode(err)).To(Equal(services.ExitFailure))
+	})
+
+	It("should finish the resources when the reporter panics", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		resourceA := NewMockResource(ctrl)
+		resourceB := NewMockResource(ctrl)
+
+		reporter := NewMockReporter(ctrl)
+		gomock.InOrder(
+			reporter.EXPECT().BeforeStart(gomock.Any(), resourceA),
+			resourceA.EXPECT().Start(gomock.Any()),
+			reporter.EXPECT().AfterStart(gomock.Any(), resourceA, nil),
+			reporter.EXPECT().BeforeStart(gomock.Any(), resourceB).Do(func(context.Context, services.Service) {
+				panic("random panic")
+			}),
+			reporter.EXPECT().BeforeStop(gomock.Any(), resourceA),
+			resourceA.EXPECT().Stop(gomock.Any()),
+			reporter.EXPECT().AfterStop(gomock.Any(), resourceA, nil),
+		)
+
+		runner := services.NewRunner(services.WithReporter(reporter))
+		Expect(func() {
+			_ = runner.Serve(ctx, []services.Service{resourceA, resourceB})
+		}).To(PanicWith("random panic"))
+	})
+
+	It("should return the exit code of the signal received", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		closed := make(chan struct{})
+		resourceA := NewMockResource(ctrl)
+		serverA := NewMockServer(ctrl)
+
+		// Each Run creates its own listener: the first one is used for starting the resources, the second for the servers.
+		listeners := make(chan signaltest.MockListener, 2)
+		gomock.InOrder(
+			resourceA.EXPECT().Start(gomock.Any()),
+			serverA.EXPECT().Listen(gomock.Any()).Do(func(context.Context) {
+				<-listeners
+				go (<-listeners).Send(os.Interrupt)
+				<-closed
+			}),
+			serverA.EXPECT().Close(gomock.Any()).Do(func(context.Context) {
+				close(closed)
+			}),
+			resourceA.EXPECT().Stop(gomock.Any()),
+		)
+
+		code := services.Main(ctx, []services.StarterOption{
+			services.WithListenerBuilder(func() signals.Listener {
+				listener := signaltest.NewMockListener(os.Interrupt)
+				listeners <- listener
+				return listener
+			}),
+		}, []services.Service{resourceA}, serverA)
+		Expect(code).To(Equal(130))
+	})
+
+	Describe("ExitCode", func() {
+		It("should map the errors to exit codes", func() {
+			sigint := &services.SignalError{Signal: syscall.SIGINT}
+			sigterm := &services.SignalError{Signal: syscall.SIGTERM}
+			randomErr := errors.New("random error")
+
+			Expect(services.ExitCode(nil)).To(Equal(services.ExitOK))
+			Expect(services.ExitCode(context.Canceled)).To(Equal(services.ExitOK))
+			Expect(services.ExitCode(services.MultiErrors{nil, context.Canceled})).To(Equal(services.ExitOK))
+			Expect(services.ExitCode(sigint)).To(Equal(130))
+			Expect(services.ExitCode(services.MultiErrors{sigterm, sigint})).To(Equal(143))
+			Expect(services.ExitCode(randomErr)).To(Equal(services.ExitFailure))
+			Expect(services.ExitCode(services.MultiErrors{sigint, randomErr})).To(Equal(services.ExitFailure))
+			Expect(services.ExitCode(services.MultiErrors{services.MultiErrors{sigint, nil}, randomErr})).To(Equal(services.ExitFailure))
+		})
+	})
+})
@@ -43,7 +43,7 @@ type Server interface {
 
 	// Listen will start the server and will block until the service is closed.
 	//
-	// If the services is already listining, this should return an error ErrAlreadyListening.
+	// If the services is already listining, this should return ErrAlreadyListening.
 	Listen(ctx context.Context) error
 
 	// Close will stop this service.
@@ -51,3 +51,39 @@ type Server interface {
 	// If the services has not started, or is already stopped, this should do nothing and just return nil.
 	Close(ctx context.Context) error
 }
+
+// ReadyNotifier describes a Server that notifies when it is ready to accept work (e.g. when it is bound to its
+// address). When a Server implements it, Runner.Run waits the returned channel to be closed before considering the
+// server running (check WithStartupTimeout).
+type ReadyNotifier interface {
+	// Ready returns a channel that is closed when the server is ready.
+	Ready() <-chan struct{}
+}
+
+// Wrapper describes a Service that wraps another one, like the ones built by the Retrier.
+//
+// The Runner looks through wrappers for the optional interfaces (Configurable, HealthChecker, ReadyNotifier, ...) of
+// the services they wrap (check As).
+type Wrapper interface {
+	// Unwrap returns the wrapped service.
+	Unwrap() Service
+}
+
+// As returns the implementation of T of the given service. If the service does not implement T, the services it wraps
+// are checked, in order (check Wrapper).
+//
+// Ex: checker, ok := services.As[services.HealthChecker](service)
+func As[T any](service Service) (T, bool) {
+	for service != nil {
+		if impl, ok := service.(T); ok {
+			return impl, true
+		}
+		wrapper, ok := service.(Wrapper)
+		if !ok {
+			break
+		}
+		service = wrapper.Unwrap()
+	}
+	var zero T
+	return zero, false
+}
@@ -1,4 +1,4 @@
-//go:generate go run github.com/golang/mock/mockgen -destination=mocks_test.go -package services_test . Resource,Server,Reporter,Configurable,RetrierReporter
+//go:generate go run github.com/golang/mock/mockgen -destination=mocks_test.go -package services_test . Resource,Server,Reporter,Configurable,RetrierReporter,AttemptReporter,Dependent,HealthChecker,SupervisorReporter,ShutdownTimeouter,PhaseReporter,PanicReporter,Reloadable,ReloadReporter,RunReporter
 package services_test
 
 import (
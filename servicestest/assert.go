@@ -0,0 +1,112 @@
+package servicestest
+
+import (
+	"github.com/setare/go-services"
+)
+
+// TestingT is the subset of `testing.TB` used by the assertions. `ginkgo.GinkgoT()` also implements it.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// AssertStartedInOrder checks that the given services were successfully started, once each, in the given order. Other
+// services recorded by the reporter are ignored.
+func AssertStartedInOrder(t TestingT, reporter *Reporter, ss ...services.Service) bool {
+	t.Helper()
+	want := serviceNames(ss)
+	got := make([]string, 0, len(ss))
+	for _, event := range reporter.Filter(AfterStart) {
+		if event.Err == nil && contains(want, event.Name) {
+			got = append(got, event.Name)
+		}
+	}
+	if !equalNames(got, want) {
+		t.Errorf("expected the services to be started in the order %q, but they were started in the order %q", want, got)
+		return false
+	}
+	return true
+}
+
+// AssertStoppedInReverseOrder checks that the given services, listed in the order they were started, were stopped
+// once each in the reverse order (accordingly to their AfterStop events, which are reported for resources and servers).
+// Other services recorded by the reporter are ignored.
+func AssertStoppedInReverseOrder(t TestingT, reporter *Reporter, ss ...services.Service) bool {
+	t.Helper()
+	names := serviceNames(ss)
+	want := make([]string, len(names))
+	for idx, name := range names {
+		want[len(names)-1-idx] = name
+	}
+	got := make([]string, 0, len(ss))
+	for _, event := range reporter.Filter(AfterStop) {
+		if contains(want, event.Name) {
+			got = append(got, event.Name)
+		}
+	}
+	if !equalNames(got, want) {
+		t.Errorf("expected the services to be stopped in the order %q, but they were stopped in the order %q", want, got)
+		return false
+	}
+	return true
+}
+
+// AssertNoFailures checks that no event recorded by the reporter has an error. The errors of BeforeRestart and
+// AfterRetry are ignored, since they are the causes of restarts and retries (a retrier that gives up fails its start).
+// The errors of AfterRun and AfterFinish are also ignored, since they are the results of Runner.Run (which might be
+// stopped by a signal or its ctx) and Runner.Finish.
+func AssertNoFailures(t TestingT, reporter *Reporter) bool {
+	t.Helper()
+	ok := true
+	for _, event := range reporter.Events() {
+		if event.Err == nil || event.Kind == BeforeRestart || event.Kind == AfterRetry || event.Kind == AfterRun ||
+			event.Kind == AfterFinish {
+			continue
+		}
+		t.Errorf("unexpected failure on %s of %q: %v", event.Kind, event.Name, event.Err)
+		ok = false
+	}
+	return ok
+}
+
+// AssertReported checks that the reporter recorded, at least, one event with the given kind for the given service.
+func AssertReported(t TestingT, reporter *Reporter, kind EventKind, service services.Service) bool {
+	t.Helper()
+	name := service.Name()
+	for _, event := range reporter.Filter(kind) {
+		if event.Name == name {
+			return true
+		}
+	}
+	t.Errorf("expected %s of %q to be reported", kind, name)
+	return false
+}
+
+func serviceNames(ss []services.Service) []string {
+	names := make([]string, len(ss))
+	for idx, service := range ss {
+		names[idx] = service.Name()
+	}
+	return names
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func equalNames(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for idx := range a {
+		if a[idx] != b[idx] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,140 @@
+package servicestest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/setare/go-services"
+)
+
+// EventKind identifies the method of the Reporter that recorded an Event.
+type EventKind = services.EventKind
+
+// The kinds of events, named after the methods of the Reporter.
+const (
+	BeforeStart         = services.EventBeforeStart
+	AfterStart          = services.EventAfterStart
+	BeforeStop          = services.EventBeforeStop
+	AfterStop           = services.EventAfterStop
+	BeforeLoad          = services.EventBeforeLoad
+	AfterLoad           = services.EventAfterLoad
+	SignalReceived      = services.EventSignalReceived
+	BeforeRetry         = services.EventBeforeRetry
+	AfterRetry          = services.EventAfterRetry
+	BeforeRestart       = services.EventBeforeRestart
+	BeforeShutdownPhase = services.EventBeforeShutdownPhase
+	AfterShutdownPhase  = services.EventAfterShutdownPhase
+	PanicRecovered      = services.EventPanicRecovered
+	BeforeReload        = services.EventBeforeReload
+	AfterReload         = services.EventAfterReload
+	BeforeRun           = services.EventBeforeRun
+	AfterRun            = services.EventAfterRun
+	BeforeFinish        = services.EventBeforeFinish
+	AfterFinish         = services.EventAfterFinish
+)
+
+// Event is a call recorded by the Reporter. Only the fields related to its Kind are set.
+type Event struct {
+	services.Event
+	// Name is the name of the Service.
+	Name string
+}
+
+type eventWaiter struct {
+	kind EventKind
+	name string
+	ch   chan struct{}
+}
+
+// Reporter records all events it is notified about, in order. It implements all the reporter interfaces of the
+// services package, through the embedded services.EventReporter, and it is safe for concurrent use. Create it with
+// NewReporter.
+type Reporter struct {
+	*services.EventReporter
+	mutex   sync.Mutex
+	events  []Event
+	waiters []eventWaiter
+}
+
+// NewReporter creates a new Reporter.
+func NewReporter() *Reporter {
+	reporter := &Reporter{}
+	reporter.EventReporter = services.NewEventReporter(reporter.record)
+	return reporter
+}
+
+// Events returns a copy of the events recorded until now.
+func (reporter *Reporter) Events() []Event {
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+	events := make([]Event, len(reporter.events))
+	copy(events, reporter.events)
+	return events
+}
+
+// Filter returns the events recorded until now with the given kind.
+func (reporter *Reporter) Filter(kind EventKind) []Event {
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+	events := make([]Event, 0)
+	for _, event := range reporter.events {
+		if event.Kind == kind {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// Names returns the name of the service of each event recorded until now with the given kind.
+func (reporter *Reporter) Names(kind EventKind) []string {
+	events := reporter.Filter(kind)
+	names := make([]string, len(events))
+	for idx, event := range events {
+		names[idx] = event.Name
+	}
+	return names
+}
+
+// Reset discards all events recorded until now.
+func (reporter *Reporter) Reset() {
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+	reporter.events = nil
+}
+
+// Wait returns a channel that is closed when an event with the given kind, of the service with the given name, is
+// recorded. If there is already one, the returned channel is closed. An empty name matches any service.
+func (reporter *Reporter) Wait(kind EventKind, name string) <-chan struct{} {
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+	ch := make(chan struct{})
+	for _, event := range reporter.events {
+		if event.Kind == kind && (name == "" || event.Name == name) {
+			close(ch)
+			return ch
+		}
+	}
+	reporter.waiters = append(reporter.waiters, eventWaiter{kind, name, ch})
+	return ch
+}
+
+// record appends the event, releasing the waiters it matches.
+func (reporter *Reporter) record(_ context.Context, e services.Event) {
+	event := Event{Event: e}
+	if event.Service != nil {
+		event.Name = event.Service.Name()
+	}
+
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+	reporter.events = append(reporter.events, event)
+	waiters := reporter.waiters[:0]
+	for _, waiter := range reporter.waiters {
+		if waiter.kind == event.Kind && (waiter.name == "" || waiter.name == event.Name) {
+			close(waiter.ch)
+			continue
+		}
+		waiters = append(waiters, waiter)
+	}
+	reporter.waiters = waiters
+}
@@ -0,0 +1,134 @@
+package servicestest
+
+import (
+	"context"
+	"sync"
+)
+
+// Resource is a fake services.Resource whose Start and Stop are scripted by OnStart and OnStop. As required by the
+// services.Resource contract, Stop cancels the ctx of the calls to Start in progress and waits for them to return.
+type Resource struct {
+	name string
+
+	mutex    sync.Mutex
+	start    script
+	stop     script
+	starting map[*context.CancelFunc]struct{}
+	startsWg sync.WaitGroup
+}
+
+// NewResource creates a new Resource with the given name. Its Start and Stop succeed until they are scripted.
+func NewResource(name string) *Resource {
+	return &Resource{
+		name:     name,
+		starting: make(map[*context.CancelFunc]struct{}),
+	}
+}
+
+// OnStart scripts the calls to Start: each call uses the next behavior and, when all of them were used, the last one
+// is repeated.
+func (r *Resource) OnStart(behaviors ...Behavior) *Resource {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.start.behaviors = behaviors
+	return r
+}
+
+// OnStop scripts the calls to Stop, the same way as OnStart.
+func (r *Resource) OnStop(behaviors ...Behavior) *Resource {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.stop.behaviors = behaviors
+	return r
+}
+
+// Name returns the name given when the Resource was created.
+func (r *Resource) Name() string {
+	return r.name
+}
+
+// Start behaves as scripted by OnStart. Its ctx is cancelled when Stop is called.
+func (r *Resource) Start(ctx context.Context) error {
+	ctx, cancelFunc := context.WithCancel(ctx)
+	defer cancelFunc()
+
+	r.mutex.Lock()
+	behavior := r.start.next()
+	r.starting[&cancelFunc] = struct{}{}
+	r.startsWg.Add(1)
+	r.mutex.Unlock()
+
+	defer func() {
+		r.mutex.Lock()
+		delete(r.starting, &cancelFunc)
+		r.mutex.Unlock()
+		r.startsWg.Done()
+	}()
+	return behavior.run(ctx)
+}
+
+// Stop cancels the calls to Start in progress, waits for them to return, and then behaves as scripted by OnStop.
+func (r *Resource) Stop(ctx context.Context) error {
+	r.mutex.Lock()
+	behavior := r.stop.next()
+	for cancelFunc := range r.starting {
+		(*cancelFunc)()
+	}
+	r.mutex.Unlock()
+	r.startsWg.Wait()
+
+	return behavior.run(ctx)
+}
+
+// Starts returns how many times Start was called.
+func (r *Resource) Starts() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.start.calls
+}
+
+// Stops returns how many times Stop was called.
+func (r *Resource) Stops() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.stop.calls
+}
+
+// Configurable is a fake Resource that also implements services.Configurable. Its Load is scripted by OnLoad.
+type Configurable struct {
+	*Resource
+
+	loadMutex sync.Mutex
+	load      script
+}
+
+// NewConfigurable creates a new Configurable with the given name. Its Load, Start and Stop succeed until they are
+// scripted.
+func NewConfigurable(name string) *Configurable {
+	return &Configurable{
+		Resource: NewResource(name),
+	}
+}
+
+// OnLoad scripts the calls to Load, the same way as Resource.OnStart.
+func (c *Configurable) OnLoad(behaviors ...Behavior) *Configurable {
+	c.loadMutex.Lock()
+	defer c.loadMutex.Unlock()
+	c.load.behaviors = behaviors
+	return c
+}
+
+// Load behaves as scripted by OnLoad.
+func (c *Configurable) Load(ctx context.Context) error {
+	c.loadMutex.Lock()
+	behavior := c.load.next()
+	c.loadMutex.Unlock()
+	return behavior.run(ctx)
+}
+
+// Loads returns how many times Load was called.
+func (c *Configurable) Loads() int {
+	c.loadMutex.Lock()
+	defer c.loadMutex.Unlock()
+	return c.load.calls
+}
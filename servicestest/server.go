@@ -0,0 +1,162 @@
+package servicestest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/setare/go-services"
+)
+
+// Server is a fake services.Server. Listen behaves as scripted by OnListen and, if it succeeds, the server is
+// listening: it blocks until Close is called (returning nil), the Server is crashed by Crash (returning the given
+// error) or the ctx is done (returning the ctx error). A Server can listen again after Listen returns.
+//
+// The Server implements services.ReadyNotifier, it is ready once it is listening for the first time. So, the
+// services.Runner only considers it running after OnListen behavior succeeds.
+type Server struct {
+	name string
+
+	mutex     sync.Mutex
+	listen    script
+	close     script
+	listens   int
+	listening bool
+	closeCh   chan struct{}
+	crashCh   chan error
+	ready     chan struct{}
+	readyOnce sync.Once
+}
+
+// NewServer creates a new Server with the given name. Its Listen and Close succeed until they are scripted.
+func NewServer(name string) *Server {
+	return &Server{
+		name:  name,
+		ready: make(chan struct{}),
+	}
+}
+
+// OnListen scripts the calls to Listen, before the Server is listening: each call uses the next behavior and, when all
+// of them were used, the last one is repeated. When the behavior fails, Listen returns the error without listening.
+func (s *Server) OnListen(behaviors ...Behavior) *Server {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.listen.behaviors = behaviors
+	return s
+}
+
+// OnClose scripts the calls to Close, the same way as OnListen. The Listen is released after the behavior, even if it
+// fails or panics.
+func (s *Server) OnClose(behaviors ...Behavior) *Server {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.close.behaviors = behaviors
+	return s
+}
+
+// Name returns the name given when the Server was created.
+func (s *Server) Name() string {
+	return s.name
+}
+
+// Ready returns a channel that is closed when the Server is listening for the first time.
+func (s *Server) Ready() <-chan struct{} {
+	return s.ready
+}
+
+// Listen behaves as scripted by OnListen and, if it succeeds, blocks until the Server is closed, crashed or the ctx is
+// done.
+//
+// If the Server is already listening, services.ErrAlreadyListening is returned.
+func (s *Server) Listen(ctx context.Context) error {
+	s.mutex.Lock()
+	s.listens++
+	if s.listening {
+		s.mutex.Unlock()
+		return services.ErrAlreadyListening
+	}
+	behavior := s.listen.next()
+	s.mutex.Unlock()
+
+	if err := behavior.run(ctx); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	if s.listening {
+		s.mutex.Unlock()
+		return services.ErrAlreadyListening
+	}
+	closeCh, crashCh := make(chan struct{}), make(chan error, 1)
+	s.listening, s.closeCh, s.crashCh = true, closeCh, crashCh
+	s.mutex.Unlock()
+	s.readyOnce.Do(func() {
+		close(s.ready)
+	})
+
+	defer func() {
+		s.mutex.Lock()
+		s.listening, s.closeCh, s.crashCh = false, nil, nil
+		s.mutex.Unlock()
+	}()
+
+	select {
+	case <-closeCh:
+		return nil
+	case err := <-crashCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close behaves as scripted by OnClose and releases the Listen, if the Server is listening. Otherwise, it does nothing
+// besides the scripted behavior.
+func (s *Server) Close(ctx context.Context) error {
+	s.mutex.Lock()
+	behavior := s.close.next()
+	s.mutex.Unlock()
+
+	defer func() {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		if s.closeCh != nil {
+			close(s.closeCh)
+			s.closeCh = nil
+		}
+	}()
+	return behavior.run(ctx)
+}
+
+// Crash makes the Listen return the given error, as if the server had failed while listening. It returns false when
+// the Server is not listening.
+func (s *Server) Crash(err error) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if !s.listening || s.crashCh == nil {
+		return false
+	}
+	s.crashCh <- err
+	s.crashCh = nil
+	return true
+}
+
+// Listening checks if the Server is listening.
+func (s *Server) Listening() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.listening
+}
+
+// Listens returns how many times Listen was called.
+func (s *Server) Listens() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.listens
+}
+
+// Closes returns how many times Close was called.
+func (s *Server) Closes() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.close.calls
+}
@@ -0,0 +1,114 @@
+// Package servicestest implements helpers for testing code built on top of the services package: fake services whose
+// behavior is scriptable (check Behavior), a Reporter that records the timeline of events, a fake signal listener and
+// assertions over the recorded events.
+//
+//	db := servicestest.NewResource("Database").OnStart(servicestest.Fail(err), servicestest.Succeed())
+//	reporter := servicestest.NewReporter()
+//	runner := services.NewRunner(services.WithReporter(reporter))
+package servicestest
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Behavior scripts how a call to a fake service behaves. When called, the fake waits the Gate to be released, then it
+// waits the Delay, then it panics with Panic (when not nil), otherwise it returns Err. Waiting is cancelled by the ctx
+// given to the call, in that case the ctx error is returned.
+//
+// The zero value returns nil immediately.
+type Behavior struct {
+	Gate  *Gate
+	Delay time.Duration
+	Panic interface{}
+	Err   error
+}
+
+// Succeed returns a Behavior that returns nil immediately.
+func Succeed() Behavior {
+	return Behavior{}
+}
+
+// Fail returns a Behavior that returns the given error.
+func Fail(err error) Behavior {
+	return Behavior{Err: err}
+}
+
+// Panic returns a Behavior that panics with the given value.
+func Panic(value interface{}) Behavior {
+	return Behavior{Panic: value}
+}
+
+// Sleep returns a Behavior that returns nil after the given delay.
+func Sleep(delay time.Duration) Behavior {
+	return Behavior{Delay: delay}
+}
+
+// Block returns a Behavior that blocks until the given Gate is released.
+func Block(gate *Gate) Behavior {
+	return Behavior{Gate: gate}
+}
+
+// run executes the behavior.
+func (b Behavior) run(ctx context.Context) error {
+	if b.Gate != nil {
+		select {
+		case <-b.Gate.ch:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if b.Delay > 0 {
+		timer := time.NewTimer(b.Delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if b.Panic != nil {
+		panic(b.Panic)
+	}
+	return b.Err
+}
+
+// Gate blocks the calls whose Behavior use it until it is released.
+type Gate struct {
+	ch   chan struct{}
+	once sync.Once
+}
+
+// NewGate creates a new Gate that blocks until Release is called.
+func NewGate() *Gate {
+	return &Gate{
+		ch: make(chan struct{}),
+	}
+}
+
+// Release unblocks all calls waiting, or that will wait, the Gate. It can be called many times.
+func (gate *Gate) Release() {
+	gate.once.Do(func() {
+		close(gate.ch)
+	})
+}
+
+// script is the sequence of behaviors of a method of a fake. Each call uses the next behavior, the last one is repeated
+// once all others were used.
+type script struct {
+	behaviors []Behavior
+	calls     int
+}
+
+// next counts a call returning its behavior.
+func (s *script) next() Behavior {
+	s.calls++
+	if len(s.behaviors) == 0 {
+		return Behavior{}
+	}
+	if s.calls > len(s.behaviors) {
+		return s.behaviors[len(s.behaviors)-1]
+	}
+	return s.behaviors[s.calls-1]
+}
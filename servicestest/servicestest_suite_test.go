@@ -0,0 +1,13 @@
+package servicestest_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestServicestest(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Servicestest Tests")
+}
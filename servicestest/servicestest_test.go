@@ -0,0 +1,247 @@
+package servicestest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/setare/go-services"
+	"github.com/setare/go-services/servicestest"
+)
+
+// recordingT is a servicestest.TestingT that records the failures.
+type recordingT struct {
+	errors []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+var _ = Describe("Fakes", func() {
+	var _ services.Resource = &servicestest.Resource{}
+	var _ services.Configurable = &servicestest.Configurable{}
+	var _ services.Server = &servicestest.Server{}
+	var _ services.ReadyNotifier = &servicestest.Server{}
+	var _ services.ReloadReporter = &servicestest.Reporter{}
+	var _ services.RetrierReporter = &servicestest.Reporter{}
+	var _ services.AttemptReporter = &servicestest.Reporter{}
+	var _ services.SupervisorReporter = &servicestest.Reporter{}
+	var _ services.PhaseReporter = &servicestest.Reporter{}
+	var _ services.PanicReporter = &servicestest.Reporter{}
+	var _ services.RunReporter = &servicestest.Reporter{}
+
+	It("should run the whole lifecycle", func() {
+		ctx, cancelFunc := context.WithCancel(context.TODO())
+		defer cancelFunc()
+
+		config := servicestest.NewConfigurable("Config")
+		database := servicestest.NewResource("Database")
+		server := servicestest.NewServer("Server")
+
+		reporter := servicestest.NewReporter()
+		runner := services.NewRunner(services.WithReporter(reporter))
+
+		served := make(chan error)
+		go func() {
+			served <- runner.Serve(ctx, []services.Service{config, database}, server)
+		}()
+		Eventually(reporter.Wait(servicestest.AfterStart, "Server")).Should(BeClosed())
+		Expect(server.Listening()).To(BeTrue())
+		cancelFunc()
+		Eventually(served).Should(Receive(MatchError(context.Canceled)))
+
+		Expect(server.Listening()).To(BeFalse())
+		Expect(config.Loads()).To(Equal(1))
+		Expect(config.Starts()).To(Equal(1))
+		Expect(config.Stops()).To(Equal(1))
+		Expect(database.Starts()).To(Equal(1))
+		Expect(database.Stops()).To(Equal(1))
+		Expect(server.Listens()).To(Equal(1))
+		Expect(server.Closes()).To(Equal(1))
+
+		Expect(servicestest.AssertStartedInOrder(GinkgoT(), reporter, config, database, server)).To(BeTrue())
+		Expect(servicestest.AssertStoppedInReverseOrder(GinkgoT(), reporter, config, database, server)).To(BeTrue())
+		Expect(servicestest.AssertNoFailures(GinkgoT(), reporter)).To(BeTrue())
+		Expect(servicestest.AssertReported(GinkgoT(), reporter, servicestest.AfterLoad, config)).To(BeTrue())
+	})
+
+	It("should follow the scripted behaviors in order, repeating the last one", func() {
+		ctx := context.TODO()
+
+		wantErr := errors.New("connection refused")
+		database := servicestest.NewResource("Database").OnStart(
+			servicestest.Fail(wantErr),
+			servicestest.Fail(wantErr),
+			servicestest.Succeed(),
+		)
+
+		reporter := servicestest.NewReporter()
+		retrier := services.Retrier().Backoff(&backoff.ZeroBackOff{}).Reporter(reporter).Build(database)
+		Expect(retrier.Start(ctx)).To(Succeed())
+		Expect(database.Starts()).To(Equal(3))
+		Expect(database.Start(ctx)).To(Succeed())
+
+		retries := reporter.Filter(servicestest.AfterRetry)
+		Expect(retries).To(HaveLen(3))
+		Expect(retries[0].Attempt).To(Equal(1))
+		Expect(retries[0].Err).To(MatchError(wantErr))
+		Expect(retries[2].Err).ToNot(HaveOccurred())
+		Expect(servicestest.AssertNoFailures(GinkgoT(), reporter)).To(BeTrue())
+	})
+
+	It("should panic as scripted", func() {
+		ctx := context.TODO()
+
+		database := servicestest.NewResource("Database").OnStart(servicestest.Panic("boom"))
+
+		reporter := servicestest.NewReporter()
+		runner := services.NewRunner(services.WithReporter(reporter))
+		err := runner.Run(ctx, database)
+		Expect(err).To(MatchError(services.ErrPanic))
+
+		panics := reporter.Filter(servicestest.PanicRecovered)
+		Expect(panics).To(HaveLen(1))
+		Expect(panics[0].Name).To(Equal("Database"))
+		Expect(panics[0].Panic.Operation).To(Equal("start"))
+		Expect(panics[0].Panic.Value).To(Equal("boom"))
+	})
+
+	It("should block until the gate is released or the ctx is done", func() {
+		gate := servicestest.NewGate()
+		database := servicestest.NewResource("Database").OnStart(servicestest.Block(gate))
+
+		started := make(chan error)
+		go func() {
+			started <- database.Start(context.TODO())
+		}()
+		Consistently(started, 20*time.Millisecond).ShouldNot(Receive())
+		gate.Release()
+		Eventually(started).Should(Receive(BeNil()))
+
+		ctx, cancelFunc := context.WithCancel(context.TODO())
+		database.OnStop(servicestest.Block(servicestest.NewGate()))
+		go func() {
+			started <- database.Stop(ctx)
+		}()
+		cancelFunc()
+		Eventually(started).Should(Receive(MatchError(context.Canceled)))
+	})
+
+	It("should listen until the server is closed or crashed", func() {
+		ctx := context.TODO()
+
+		server := servicestest.NewServer("Server")
+		Expect(server.Close(ctx)).To(Succeed())
+		Expect(server.Crash(errors.New("not listening"))).To(BeFalse())
+
+		listened := make(chan error)
+		go func() {
+			listened <- server.Listen(ctx)
+		}()
+		Eventually(server.Ready()).Should(BeClosed())
+		Expect(server.Listen(ctx)).To(MatchError(services.ErrAlreadyListening))
+		Expect(server.Close(ctx)).To(Succeed())
+		Eventually(listened).Should(Receive(BeNil()))
+
+		wantErr := errors.New("connection reset")
+		go func() {
+			listened <- server.Listen(ctx)
+		}()
+		Eventually(server.Listening).Should(BeTrue())
+		Expect(server.Crash(wantErr)).To(BeTrue())
+		Eventually(listened).Should(Receive(Equal(wantErr)))
+		Expect(server.Listens()).To(Equal(3))
+	})
+
+	It("should not listen when the scripted behavior fails", func() {
+		wantErr := errors.New("address already in use")
+		server := servicestest.NewServer("Server").OnListen(servicestest.Fail(wantErr))
+
+		runner := services.NewRunner()
+		Expect(runner.Run(context.TODO(), server)).To(MatchError(wantErr))
+		Expect(server.Listening()).To(BeFalse())
+	})
+
+	It("should wait for the listener to be built before sending signals", func() {
+		listener := servicestest.NewSignalListener(os.Interrupt)
+
+		gate := servicestest.NewGate()
+		database := servicestest.NewResource("Database").OnStart(servicestest.Block(gate))
+
+		reporter := servicestest.NewReporter()
+		runner := services.NewRunner(
+			services.WithReporter(reporter),
+			services.WithListenerBuilder(listener.Builder()),
+		)
+
+		ran := make(chan error)
+		go func() {
+			ran <- runner.Run(context.TODO(), database)
+		}()
+
+		// Ignored, since the listener only receives os.Interrupt.
+		listener.Send(syscall.SIGTERM)
+		listener.Send(os.Interrupt)
+
+		Eventually(ran).Should(Receive(MatchError(services.ErrStartCancelledBySignal)))
+		Expect(listener.Builds()).To(Equal(1))
+		Expect(reporter.Filter(servicestest.SignalReceived)).To(HaveLen(1))
+	})
+
+	It("should wait for events", func() {
+		reporter := servicestest.NewReporter()
+		database := servicestest.NewResource("Database")
+
+		waitAny := reporter.Wait(servicestest.BeforeStop, "")
+		waitDatabase := reporter.Wait(servicestest.BeforeStop, "Database")
+		waitOther := reporter.Wait(servicestest.BeforeStop, "Other")
+		Expect(waitAny).ToNot(BeClosed())
+
+		reporter.BeforeStop(context.TODO(), database)
+		Expect(waitAny).To(BeClosed())
+		Expect(waitDatabase).To(BeClosed())
+		Expect(waitOther).ToNot(BeClosed())
+		Expect(reporter.Wait(servicestest.BeforeStop, "Database")).To(BeClosed())
+		Expect(reporter.Names(servicestest.BeforeStop)).To(Equal([]string{"Database"}))
+
+		reporter.Reset()
+		Expect(reporter.Events()).To(BeEmpty())
+	})
+
+	Describe("Assertions", func() {
+		It("should fail when the services are not started and stopped in order", func() {
+			ctx := context.TODO()
+
+			serviceA := servicestest.NewResource("Service A")
+			serviceB := servicestest.NewResource("Service B")
+			serviceC := servicestest.NewResource("Service C").OnStop(servicestest.Fail(errors.New("stop failed")))
+
+			reporter := servicestest.NewReporter()
+			runner := services.NewRunner(services.WithReporter(reporter))
+			Expect(runner.Run(ctx, serviceA, serviceB, serviceC)).To(Succeed())
+			Expect(runner.Finish(ctx)).ToNot(Succeed())
+
+			t := &recordingT{}
+			Expect(servicestest.AssertStartedInOrder(t, reporter, serviceB, serviceA)).To(BeFalse())
+			Expect(servicestest.AssertStoppedInReverseOrder(t, reporter, serviceC, serviceB, serviceA)).To(BeFalse())
+			Expect(servicestest.AssertNoFailures(t, reporter)).To(BeFalse())
+			Expect(servicestest.AssertReported(t, reporter, servicestest.AfterLoad, serviceA)).To(BeFalse())
+			Expect(t.errors).To(Equal([]string{
+				`expected the services to be started in the order ["Service B" "Service A"], but they were started in the order ["Service A" "Service B"]`,
+				`expected the services to be stopped in the order ["Service A" "Service B" "Service C"], but they were stopped in the order ["Service C" "Service B" "Service A"]`,
+				`unexpected failure on AfterStop of "Service C": stop failed`,
+				`expected AfterLoad of "Service A" to be reported`,
+			}))
+		})
+	})
+})
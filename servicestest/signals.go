@@ -0,0 +1,131 @@
+package servicestest
+
+import (
+	"os"
+	"sync"
+
+	signals "github.com/jamillosantos/go-os-signals"
+)
+
+// SignalListener fakes the signals received by a services.Runner. Since the Runner stops the listener it builds when
+// it is done, a `signals.Listener` cannot be reused: Builder returns a builder (for services.WithListenerBuilder, or
+// services.WithReloadListenerBuilder) that creates a new listener on each call, and Send delivers the signal to the
+// latest one.
+type SignalListener struct {
+	signals []os.Signal
+
+	mutex   sync.Mutex
+	current *fakeListener
+	changed chan struct{}
+	builds  int
+}
+
+// NewSignalListener creates a new SignalListener whose listeners receive only the given signals. When no signals are
+// given, all signals are received.
+func NewSignalListener(ss ...os.Signal) *SignalListener {
+	return &SignalListener{
+		signals: ss,
+		changed: make(chan struct{}),
+	}
+}
+
+// Builder returns a function that builds a new listener, which becomes the one receiving the signals sent by Send.
+func (l *SignalListener) Builder() func() signals.Listener {
+	return func() signals.Listener {
+		listener := &fakeListener{
+			ch:   make(chan os.Signal),
+			done: make(chan struct{}),
+		}
+
+		l.mutex.Lock()
+		defer l.mutex.Unlock()
+		l.builds++
+		l.current = listener
+		close(l.changed)
+		l.changed = make(chan struct{})
+		return listener
+	}
+}
+
+// Builds returns how many listeners were built.
+func (l *SignalListener) Builds() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.builds
+}
+
+// Send delivers the signal to the latest listener built, blocking until it is received. If there is no listener
+// listening, it waits for one to be built. Signals that the SignalListener does not receive are dropped.
+func (l *SignalListener) Send(sig os.Signal) {
+	if !l.receives(sig) {
+		return
+	}
+	for {
+		l.mutex.Lock()
+		listener, changed := l.current, l.changed
+		l.mutex.Unlock()
+
+		if listener != nil && listener.send(sig) {
+			return
+		}
+		// The current listener was stopped, or there is none yet.
+		l.mutex.Lock()
+		if l.current == listener && listener != nil {
+			l.current = nil
+		}
+		l.mutex.Unlock()
+		if listener == nil {
+			<-changed
+		}
+	}
+}
+
+func (l *SignalListener) receives(sig os.Signal) bool {
+	if len(l.signals) == 0 {
+		return true
+	}
+	for _, s := range l.signals {
+		if s == sig {
+			return true
+		}
+	}
+	return false
+}
+
+// fakeListener is the signals.Listener built by SignalListener.
+type fakeListener struct {
+	mutex    sync.RWMutex
+	ch       chan os.Signal
+	done     chan struct{}
+	stopOnce sync.Once
+	stopped  bool
+}
+
+func (l *fakeListener) Receive() <-chan os.Signal {
+	return l.ch
+}
+
+func (l *fakeListener) Stop() {
+	l.stopOnce.Do(func() {
+		close(l.done)
+		l.mutex.Lock()
+		defer l.mutex.Unlock()
+		l.stopped = true
+		close(l.ch)
+	})
+}
+
+// send delivers the signal returning false when the listener was stopped before receiving it.
+func (l *fakeListener) send(sig os.Signal) bool {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	if l.stopped {
+		return false
+	}
+	select {
+	case l.ch <- sig:
+		return true
+	case <-l.done:
+		return false
+	}
+}
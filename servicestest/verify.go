@@ -0,0 +1,220 @@
+package servicestest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/setare/go-services"
+)
+
+const (
+	defaultVerifyTimeout = 5 * time.Second
+	defaultSettleTime    = 100 * time.Millisecond
+)
+
+type verifyConfig struct {
+	timeout    time.Duration
+	settleTime time.Duration
+}
+
+// VerifyOption configures VerifyServer and VerifyResource.
+type VerifyOption = func(*verifyConfig)
+
+// WithTimeout is a VerifyOption that sets how long each call can take before the check fails. The default is 5s.
+func WithTimeout(timeout time.Duration) VerifyOption {
+	return func(cfg *verifyConfig) {
+		cfg.timeout = timeout
+	}
+}
+
+// WithSettleTime is a VerifyOption that sets how long to wait for a call, running in background, to be in progress.
+// It is used when the server does not implement services.ReadyNotifier. The default is 100ms.
+func WithSettleTime(settleTime time.Duration) VerifyOption {
+	return func(cfg *verifyConfig) {
+		cfg.settleTime = settleTime
+	}
+}
+
+func newVerifyConfig(opts []VerifyOption) *verifyConfig {
+	cfg := &verifyConfig{
+		timeout:    defaultVerifyTimeout,
+		settleTime: defaultSettleTime,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// call runs fn in background, returning a channel that receives its result.
+func call(fn func() error) <-chan error {
+	ch := make(chan error, 1)
+	go func() {
+		ch <- fn()
+	}()
+	return ch
+}
+
+// wait waits the result of a call, failing the test when it takes longer than the timeout.
+func (cfg *verifyConfig) wait(t *testing.T, ch <-chan error, what string) error {
+	t.Helper()
+	select {
+	case err := <-ch:
+		return err
+	case <-time.After(cfg.timeout):
+		t.Fatalf("%s did not return within %s", what, cfg.timeout)
+		return nil
+	}
+}
+
+// listening waits for the server to be listening: until it is ready, if it implements services.ReadyNotifier, or
+// during the settle time. It fails the test if the Listen returns before.
+func (cfg *verifyConfig) listening(t *testing.T, server services.Server, listenErr <-chan error) {
+	t.Helper()
+	var ready <-chan struct{}
+	if notifier, ok := services.As[services.ReadyNotifier](server); ok {
+		ready = notifier.Ready()
+	}
+	timeout := cfg.timeout
+	if ready == nil {
+		timeout = cfg.settleTime
+	}
+	select {
+	case <-ready:
+	case err := <-listenErr:
+		t.Fatalf("Listen returned before the server was closed: %v", err)
+	case <-time.After(timeout):
+		if ready != nil {
+			t.Fatalf("the server was not ready within %s", cfg.timeout)
+		}
+	}
+}
+
+// VerifyServer checks that the servers created by the factory comply with the services.Server contract:
+//
+//   - Close returns nil when the server was not started;
+//   - Listen blocks until Close is called, then it returns nil;
+//   - Listen returns services.ErrAlreadyListening when the server is already listening;
+//   - Close returns nil when the server is already closed.
+//
+// Each check runs as a subtest with a new server.
+func VerifyServer(t *testing.T, factory func() services.Server, opts ...VerifyOption) {
+	t.Helper()
+	cfg := newVerifyConfig(opts)
+
+	t.Run("Close before Listen", func(t *testing.T) {
+		server := factory()
+		err := cfg.wait(t, call(func() error {
+			return server.Close(context.Background())
+		}), "Close")
+		if err != nil {
+			t.Errorf("Close of a server that was not started should return nil, got: %v", err)
+		}
+	})
+
+	t.Run("Listen until closed", func(t *testing.T) {
+		server := factory()
+		listenErr := call(func() error {
+			return server.Listen(context.Background())
+		})
+		cfg.listening(t, server, listenErr)
+
+		if err := cfg.wait(t, call(func() error {
+			return server.Close(context.Background())
+		}), "Close"); err != nil {
+			t.Errorf("Close should return nil, got: %v", err)
+		}
+		if err := cfg.wait(t, listenErr, "Listen"); err != nil {
+			t.Errorf("Listen should return nil when the server is closed, got: %v", err)
+		}
+	})
+
+	t.Run("Listen twice", func(t *testing.T) {
+		server := factory()
+		listenErr := call(func() error {
+			return server.Listen(context.Background())
+		})
+		cfg.listening(t, server, listenErr)
+		defer func() {
+			_ = server.Close(context.Background())
+		}()
+
+		err := cfg.wait(t, call(func() error {
+			return server.Listen(context.Background())
+		}), "the second Listen")
+		if !errors.Is(err, services.ErrAlreadyListening) {
+			t.Errorf("Listen of a server that is already listening should return services.ErrAlreadyListening, got: %v", err)
+		}
+	})
+
+	t.Run("Close twice", func(t *testing.T) {
+		server := factory()
+		listenErr := call(func() error {
+			return server.Listen(context.Background())
+		})
+		cfg.listening(t, server, listenErr)
+
+		for _, what := range []string{"the first Close", "the second Close"} {
+			err := cfg.wait(t, call(func() error {
+				return server.Close(context.Background())
+			}), what)
+			if err != nil {
+				t.Errorf("%s should return nil, got: %v", what, err)
+			}
+		}
+		cfg.wait(t, listenErr, "Listen")
+	})
+}
+
+// VerifyResource checks that the resources created by the factory comply with the services.Resource contract:
+//
+//   - Start and Stop succeed;
+//   - Stop cancels, or waits for, a Start in progress: when Stop returns, Start has returned.
+//
+// Each check runs as a subtest with a new resource.
+func VerifyResource(t *testing.T, factory func() services.Resource, opts ...VerifyOption) {
+	t.Helper()
+	cfg := newVerifyConfig(opts)
+
+	t.Run("Start and Stop", func(t *testing.T) {
+		resource := factory()
+		if err := cfg.wait(t, call(func() error {
+			return resource.Start(context.Background())
+		}), "Start"); err != nil {
+			t.Fatalf("Start should succeed, got: %v", err)
+		}
+		if err := cfg.wait(t, call(func() error {
+			return resource.Stop(context.Background())
+		}), "Stop"); err != nil {
+			t.Errorf("Stop should succeed, got: %v", err)
+		}
+	})
+
+	t.Run("Stop while starting", func(t *testing.T) {
+		resource := factory()
+		startErr := call(func() error {
+			return resource.Start(context.Background())
+		})
+		time.Sleep(cfg.settleTime)
+
+		stopErr := call(func() error {
+			return resource.Stop(context.Background())
+		})
+		select {
+		case <-stopErr:
+			// Since the Start returned before the Stop, it should be already available. But, it is sent by a
+			// goroutine that might not have been scheduled yet.
+			select {
+			case <-startErr:
+			case <-time.After(cfg.settleTime):
+				t.Errorf("Stop returned while Start was still in progress")
+			}
+		case <-startErr:
+			cfg.wait(t, stopErr, "Stop")
+		case <-time.After(cfg.timeout):
+			t.Fatalf("Stop did not return within %s", cfg.timeout)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package servicestest_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/setare/go-services"
+	"github.com/setare/go-services/servicestest"
+)
+
+func TestVerifyServer(t *testing.T) {
+	servicestest.VerifyServer(t, func() services.Server {
+		return servicestest.NewServer("Server")
+	})
+}
+
+func TestVerifyResource(t *testing.T) {
+	servicestest.VerifyResource(t, func() services.Resource {
+		return servicestest.NewResource("Resource")
+	})
+}
+
+func TestVerifyResourceSlowStarting(t *testing.T) {
+	servicestest.VerifyResource(t, func() services.Resource {
+		return servicestest.NewResource("Resource").OnStart(servicestest.Sleep(300 * time.Millisecond))
+	})
+}
+
+// nonConformingServer does not comply with the services.Server contract: its Close fails when it is not listening, and
+// a Listen while listening blocks instead of returning services.ErrAlreadyListening.
+type nonConformingServer struct {
+	mutex   sync.Mutex
+	closeCh chan struct{}
+}
+
+func (s *nonConformingServer) Name() string { return "Non conforming" }
+
+func (s *nonConformingServer) Listen(context.Context) error {
+	s.mutex.Lock()
+	if s.closeCh == nil {
+		s.closeCh = make(chan struct{})
+	}
+	closeCh := s.closeCh
+	s.mutex.Unlock()
+
+	<-closeCh
+	return nil
+}
+
+func (s *nonConformingServer) Close(context.Context) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.closeCh == nil {
+		return errors.New("not listening")
+	}
+	close(s.closeCh)
+	s.closeCh = nil
+	return nil
+}
+
+// TestVerifyServerNonConforming runs VerifyServer against a nonConformingServer in a separate process, since its
+// failures would fail this test, and checks that the expected checks fail.
+func TestVerifyServerNonConforming(t *testing.T) {
+	if os.Getenv("VERIFY_NON_CONFORMING") == "1" {
+		servicestest.VerifyServer(t, func() services.Server {
+			return &nonConformingServer{}
+		}, servicestest.WithTimeout(200*time.Millisecond))
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestVerifyServerNonConforming$", "-test.v")
+	cmd.Env = append(os.Environ(), "VERIFY_NON_CONFORMING=1")
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("VerifyServer should fail a non conforming server, output:\n%s", output)
+	}
+	for _, want := range []string{
+		"--- FAIL: TestVerifyServerNonConforming/Close_before_Listen",
+		"Close of a server that was not started should return nil, got: not listening",
+		"--- PASS: TestVerifyServerNonConforming/Listen_until_closed",
+		"--- FAIL: TestVerifyServerNonConforming/Listen_twice",
+		"the second Listen did not return within 200ms",
+		"--- FAIL: TestVerifyServerNonConforming/Close_twice",
+		"the second Close should return nil, got: not listening",
+	} {
+		if !strings.Contains(string(output), want) {
+			t.Errorf("the output should contain %q, output:\n%s", want, output)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultShutdownPhase is the shutdown phase of the servers that were not assigned to any phase (check
+	// WithShutdownPhase).
+	DefaultShutdownPhase = "default"
+)
+
+// ShutdownTimeouter describes a service that defines how long the Runner should wait for it to stop.
+//
+// This interface is used by `Runner.Run` (when closing a Server) and by `Runner.Finish` (when stopping a Resource). It
+// takes precedence over the timeout defined by WithShutdownTimeout. A zero, or negative, timeout means that the Runner
+// will wait until the service stops.
+type ShutdownTimeouter interface {
+	// ShutdownTimeout returns how long the Runner should wait for the service to stop.
+	ShutdownTimeout() time.Duration
+}
+
+// StopTimeoutError is the error recorded when a service does not stop within its shutdown timeout. It matches
+// ErrStopTimeout when using `errors.Is`.
+type StopTimeoutError struct {
+	Service Service
+	Timeout time.Duration
+}
+
+func (err *StopTimeoutError) Error() string {
+	return fmt.Sprintf("%s: %s did not stop within %s", ErrStopTimeout, serviceName(err.Service), err.Timeout)
+}
+
+// Is implements the `errors.Is` support.
+func (err *StopTimeoutError) Is(target error) bool {
+	return target == ErrStopTimeout
+}
+
+// StopError is the error recorded when a Resource fails to stop. It wraps the error returned by Resource.Stop.
+type StopError struct {
+	Service Service
+	Err     error
+}
+
+func (err *StopError) Error() string {
+	return fmt.Sprintf("failed stopping %s: %s", serviceName(err.Service), err.Err)
+}
+
+// Unwrap returns the error returned by Resource.Stop.
+func (err *StopError) Unwrap() error {
+	return err.Err
+}
+
+type shutdownPhase struct {
+	name    string
+	servers []Server
+}
+
+// phaseServers is a shutdown phase with the indexes of the servers that belong to it.
+type phaseServers struct {
+	name    string
+	servers []int
+}
+
+// serverShutdownPhases groups the given servers by their shutdown phases (check WithShutdownPhase), in the order the
+// phases must be executed. Phases without servers are omitted.
+func (r *Runner) serverShutdownPhases(servers []Server) []phaseServers {
+	names := make([]string, 0, len(r.shutdownPhases)+1)
+	hasDefault := false
+	for _, phase := range r.shutdownPhases {
+		if phase.name == DefaultShutdownPhase {
+			hasDefault = true
+		}
+		names = append(names, phase.name)
+	}
+	if !hasDefault {
+		names = append(names, DefaultShutdownPhase)
+	}
+
+	members := make(map[string][]int, len(names))
+	for idx, server := range servers {
+		name := r.shutdownPhaseOf(server)
+		members[name] = append(members[name], idx)
+	}
+
+	phases := make([]phaseServers, 0, len(names))
+	for _, name := range names {
+		if len(members[name]) == 0 {
+			continue
+		}
+		phases = append(phases, phaseServers{
+			name:    name,
+			servers: members[name],
+		})
+		// A phase might be defined more than once.
+		delete(members, name)
+	}
+	return phases
+}
+
+// shutdownPhaseOf returns the name of the shutdown phase the given server was assigned to.
+func (r *Runner) shutdownPhaseOf(server Server) string {
+	for _, phase := range r.shutdownPhases {
+		for _, s := range phase.servers {
+			if s == server {
+				return phase.name
+			}
+		}
+	}
+	return DefaultShutdownPhase
+}
+
+// shutdownTimeout returns the shutdown timeout of the given service.
+func (r *Runner) shutdownTimeout(service Service) time.Duration {
+	if s, ok := As[ShutdownTimeouter](service); ok {
+		return s.ShutdownTimeout()
+	}
+	return r.shutdownTimeoutDefault
+}
+
+// stopWithTimeout calls stop waiting, at most, the given timeout. When the timeout is exceeded, the stop is left
+// behind and a StopTimeoutError is returned. The ctx passed to stop is cancelled when the timeout is exceeded.
+func stopWithTimeout(ctx context.Context, service Service, timeout time.Duration, stop func(context.Context) error) error {
+	if timeout <= 0 {
+		return stop(ctx)
+	}
+
+	ctx, cancelFunc := context.WithTimeout(ctx, timeout)
+	defer cancelFunc()
+
+	result := make(chan error, 1)
+	go func() {
+		result <- stop(ctx)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-result:
+		return err
+	case <-timer.C:
+		return &StopTimeoutError{
+			Service: service,
+			Timeout: timeout,
+		}
+	}
+}
+
+// joinErrors returns nil when there is no error, the error itself when there is only one, or a MultiErrors.
+func joinErrors(errs []error) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return MultiErrors(errs)
+	}
+}
@@ -0,0 +1,248 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	signals "github.com/jamillosantos/go-os-signals"
+	"github.com/jamillosantos/go-os-signals/signaltest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/setare/go-services"
+)
+
+var _ = Describe("Shutdown timeout", func() {
+	It("should move on to the next Resource when one does not stop in time", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		serviceA := NewMockResource(ctrl)
+		serviceB := NewMockResource(ctrl)
+		serviceB.EXPECT().Name().Return("Service B").AnyTimes()
+		reporter := NewMockReporter(ctrl)
+
+		gomock.InOrder(
+			reporter.EXPECT().BeforeStart(gomock.Any(), serviceA),
+			serviceA.EXPECT().Start(gomock.Any()),
+			reporter.EXPECT().AfterStart(gomock.Any(), serviceA, nil),
+			reporter.EXPECT().BeforeStart(gomock.Any(), serviceB),
+			serviceB.EXPECT().Start(gomock.Any()),
+			reporter.EXPECT().AfterStart(gomock.Any(), serviceB, nil),
+			reporter.EXPECT().BeforeStop(gomock.Any(), serviceB),
+			serviceB.EXPECT().Stop(gomock.Any()).Do(func(context.Context) {
+				time.Sleep(time.Second)
+			}),
+			reporter.EXPECT().AfterStop(gomock.Any(), serviceB, gomock.Any()).Do(func(_ context.Context, _ services.Service, err error) {
+				defer GinkgoRecover()
+				Expect(err).To(MatchError(services.ErrStopTimeout))
+			}),
+			reporter.EXPECT().BeforeStop(gomock.Any(), serviceA),
+			serviceA.EXPECT().Stop(gomock.Any()),
+			reporter.EXPECT().AfterStop(gomock.Any(), serviceA, nil),
+		)
+
+		runner := services.NewRunner(services.WithReporter(reporter), services.WithShutdownTimeout(time.Millisecond*50))
+		Expect(runner.Run(ctx, serviceA, serviceB)).To(Succeed())
+
+		startedAt := time.Now()
+		err := runner.Finish(ctx)
+		Expect(time.Since(startedAt)).To(BeNumerically("~", time.Millisecond*50, time.Millisecond*30))
+		Expect(err).To(MatchError(services.ErrStopTimeout))
+
+		var timeoutErr *services.StopTimeoutError
+		Expect(errors.As(err, &timeoutErr)).To(BeTrue())
+		Expect(timeoutErr.Service).To(Equal(serviceB))
+		Expect(timeoutErr.Error()).To(ContainSubstring("Service B did not stop within 50ms"))
+	})
+
+	It("should use the shutdown timeout defined by the service", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		serviceA := &struct {
+			*MockResource
+			*MockShutdownTimeouter
+		}{
+			MockResource:          NewMockResource(ctrl),
+			MockShutdownTimeouter: NewMockShutdownTimeouter(ctrl),
+		}
+		serviceA.MockResource.EXPECT().Name().Return("Service A").AnyTimes()
+		serviceA.MockShutdownTimeouter.EXPECT().ShutdownTimeout().Return(time.Millisecond * 50)
+		serviceA.MockResource.EXPECT().Start(gomock.Any())
+		serviceA.MockResource.EXPECT().Stop(gomock.Any()).Do(func(ctx context.Context) {
+			<-ctx.Done()
+		})
+
+		runner := services.NewRunner(services.WithShutdownTimeout(time.Hour))
+		Expect(runner.Run(ctx, serviceA)).To(Succeed())
+
+		startedAt := time.Now()
+		Expect(runner.Finish(ctx)).To(MatchError(services.ErrStopTimeout))
+		Expect(time.Since(startedAt)).To(BeNumerically("~", time.Millisecond*50, time.Millisecond*30))
+	})
+
+	It("should stop waiting a Server that does not close in time", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		serverA := NewMockServer(ctrl)
+		serverA.EXPECT().Name().Return("Server A").AnyTimes()
+		serverB := NewMockServer(ctrl)
+
+		serverA.EXPECT().Listen(gomock.Any()).Do(func(context.Context) {
+			time.Sleep(time.Second)
+		})
+		serverA.EXPECT().Close(gomock.Any())
+
+		closedB := make(chan struct{})
+		serverB.EXPECT().Listen(gomock.Any()).Do(func(context.Context) {
+			<-closedB
+		})
+		serverB.EXPECT().Close(gomock.Any()).Do(func(context.Context) {
+			close(closedB)
+		})
+
+		listener := signaltest.NewMockListener(os.Interrupt)
+		runner := services.NewRunner(services.WithShutdownTimeout(time.Millisecond*50), services.WithListenerBuilder(func() signals.Listener {
+			return listener
+		}))
+
+		go func() {
+			time.Sleep(time.Millisecond * 50)
+			listener.Send(os.Interrupt)
+		}()
+
+		startedAt := time.Now()
+		err := runner.Run(ctx, serverA, serverB)
+		Expect(time.Since(startedAt)).To(BeNumerically("~", time.Millisecond*100, time.Millisecond*40))
+		Expect(err).To(MatchError(services.ErrStopTimeout))
+		Expect(err.Error()).To(ContainSubstring("Server A"))
+	})
+
+	It("should not cancel the ctx given to Server.Close when the Run is cancelled", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx, cancelFunc := context.WithCancel(context.TODO())
+		defer cancelFunc()
+
+		serverA := NewMockServer(ctrl)
+		serverA.EXPECT().Listen(gomock.Any()).Do(func(ctx context.Context) {
+			<-ctx.Done()
+		})
+		serverA.EXPECT().Close(gomock.Any()).Do(func(ctx context.Context) {
+			defer GinkgoRecover()
+			Expect(ctx.Err()).ToNot(HaveOccurred())
+		})
+
+		runner := services.NewRunner(services.WithShutdownTimeout(time.Second))
+
+		go func() {
+			time.Sleep(time.Millisecond * 50)
+			cancelFunc()
+		}()
+
+		Expect(runner.Run(ctx, serverA)).To(MatchError(context.Canceled))
+	})
+})
+
+var _ = Describe("Shutdown phases", func() {
+	// slowServer creates a Server that listens until the ctx is cancelled and takes 100ms to close.
+	slowServer := func(ctrl *gomock.Controller, closed func()) *MockServer {
+		server := NewMockServer(ctrl)
+		server.EXPECT().Listen(gomock.Any()).Do(func(ctx context.Context) {
+			<-ctx.Done()
+		})
+		server.EXPECT().Close(gomock.Any()).Do(func(context.Context) {
+			time.Sleep(time.Millisecond * 100)
+			closed()
+		})
+		return server
+	}
+
+	It("should close all Server instances in parallel", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx, cancelFunc := context.WithCancel(context.TODO())
+
+		serverA := slowServer(ctrl, func() {})
+		serverB := slowServer(ctrl, func() {})
+		serverC := slowServer(ctrl, func() {})
+
+		go func() {
+			time.Sleep(time.Millisecond * 50)
+			cancelFunc()
+		}()
+
+		startedAt := time.Now()
+		Expect(services.NewRunner().Run(ctx, serverA, serverB, serverC)).To(MatchError(context.Canceled))
+		Expect(time.Since(startedAt)).To(BeNumerically("~", time.Millisecond*150, time.Millisecond*40))
+	})
+
+	It("should close Server instances phase by phase", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx, cancelFunc := context.WithCancel(context.TODO())
+
+		var (
+			m      sync.Mutex
+			closed []string
+		)
+		closing := func(name string) func() {
+			return func() {
+				m.Lock()
+				defer m.Unlock()
+				closed = append(closed, name)
+			}
+		}
+
+		httpServer := slowServer(ctrl, closing("http"))
+		grpcServer := slowServer(ctrl, closing("grpc"))
+		consumer := slowServer(ctrl, closing("consumer"))
+		telemetry := slowServer(ctrl, closing("telemetry"))
+
+		reporter := NewMockPhaseReporter(ctrl)
+		reporter.EXPECT().BeforeStart(gomock.Any(), gomock.Any()).Times(4)
+		reporter.EXPECT().BeforeStop(gomock.Any(), gomock.Any()).Times(4)
+		reporter.EXPECT().AfterStop(gomock.Any(), gomock.Any(), nil).Times(4)
+		gomock.InOrder(
+			reporter.EXPECT().BeforeShutdownPhase(gomock.Any(), "stop accepting traffic"),
+			reporter.EXPECT().AfterShutdownPhase(gomock.Any(), "stop accepting traffic", nil),
+			reporter.EXPECT().BeforeShutdownPhase(gomock.Any(), "drain consumers"),
+			reporter.EXPECT().AfterShutdownPhase(gomock.Any(), "drain consumers", nil),
+			reporter.EXPECT().BeforeShutdownPhase(gomock.Any(), services.DefaultShutdownPhase),
+			reporter.EXPECT().AfterShutdownPhase(gomock.Any(), services.DefaultShutdownPhase, nil),
+		)
+
+		runner := services.NewRunner(
+			services.WithReporter(reporter),
+			services.WithShutdownPhase("stop accepting traffic", httpServer, grpcServer),
+			services.WithShutdownPhase("drain consumers", consumer),
+		)
+
+		go func() {
+			time.Sleep(time.Millisecond * 50)
+			cancelFunc()
+		}()
+
+		startedAt := time.Now()
+		Expect(runner.Run(ctx, telemetry, consumer, grpcServer, httpServer)).To(MatchError(context.Canceled))
+		Expect(time.Since(startedAt)).To(BeNumerically("~", time.Millisecond*350, time.Millisecond*60))
+		Expect(closed).To(HaveLen(4))
+		Expect(closed[:2]).To(ConsistOf("http", "grpc"))
+		Expect(closed[2:]).To(Equal([]string{"consumer", "telemetry"}))
+	})
+})
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"syscall"
+
+	signals "github.com/jamillosantos/go-os-signals"
+)
+
+// SignalError is returned by Runner.Run when it is stopped by an os.Signal. It matches ErrStoppedBySignal when using
+// `errors.Is`. If the signal was received while the services were still being started, it also matches
+// ErrStartCancelledBySignal.
+type SignalError struct {
+	Signal   os.Signal
+	starting bool
+}
+
+func (err *SignalError) Error() string {
+	if err.starting {
+		return fmt.Sprintf("%s: %s", ErrStartCancelledBySignal, err.Signal)
+	}
+	return fmt.Sprintf("%s: %s", ErrStoppedBySignal, err.Signal)
+}
+
+// Is implements the `errors.Is` support.
+func (err *SignalError) Is(target error) bool {
+	return target == ErrStoppedBySignal || (err.starting && target == ErrStartCancelledBySignal)
+}
+
+// ExitCode returns the conventional exit code for a process terminated by the signal (128 + signal number).
+func (err *SignalError) ExitCode() int {
+	return signalExitCode(err.Signal)
+}
+
+// ForceExit terminates the process immediately with the conventional exit code for the given signal (128 + signal
+// number). It is meant to be used with WithSecondSignal.
+func ForceExit(sig os.Signal) {
+	os.Exit(signalExitCode(sig))
+}
+
+func signalExitCode(sig os.Signal) int {
+	if s, ok := sig.(syscall.Signal); ok {
+		return 128 + int(s)
+	}
+	return 1
+}
+
+// signalWatcher keeps track of the signals received while a Runner.Run is executing.
+type signalWatcher struct {
+	mutex        sync.Mutex
+	received     os.Signal
+	shuttingDown bool
+}
+
+// watch reads the signals from the listener until it is stopped. The first signal calls shutdown. The signals received
+// after the shutdown has begun (check beginShutdown) are given to the secondSignal, if any.
+func (w *signalWatcher) watch(listener signals.Listener, reporter Reporter, shutdown func(), secondSignal func(os.Signal)) {
+	for sig := range listener.Receive() {
+		if reporter != nil {
+			reporter.SignalReceived(sig)
+		}
+
+		w.mutex.Lock()
+		wasShuttingDown := w.shuttingDown
+		w.shuttingDown = true
+		if w.received == nil {
+			w.received = sig
+		}
+		w.mutex.Unlock()
+
+		if !wasShuttingDown {
+			shutdown()
+			continue
+		}
+		if secondSignal != nil {
+			secondSignal(sig)
+		}
+	}
+}
+
+// beginShutdown marks that the shutdown has begun, so any signal received will be considered a second signal.
+func (w *signalWatcher) beginShutdown() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.shuttingDown = true
+}
+
+// err returns the SignalError for the first signal received.
+func (w *signalWatcher) err(starting bool) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return &SignalError{
+		Signal:   w.received,
+		starting: starting,
+	}
+}
@@ -0,0 +1,254 @@
+// Package slogreporter implements a services.Reporter that logs the lifecycle events of the services using `log/slog`.
+package slogreporter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+
+	"github.com/setare/go-services"
+)
+
+const (
+	// KindResource is the kind logged for services that implement services.Resource.
+	KindResource = "resource"
+	// KindServer is the kind logged for services that implement services.Server.
+	KindServer = "server"
+	// KindConfigurable is the kind logged when loading a services.Configurable.
+	KindConfigurable = "configurable"
+)
+
+// eventKey identifies a Before event so the duration can be computed when its After counterpart is reported.
+type eventKey struct {
+	action  string
+	subject interface{}
+}
+
+// newEventKey creates the eventKey of the action on the subject. The services are identified by services.ServiceKey,
+// so the services whose types are not comparable can be used as keys.
+func newEventKey(action string, subject interface{}) eventKey {
+	if service, ok := subject.(services.Service); ok {
+		subject = services.ServiceKey(service)
+	}
+	return eventKey{action, subject}
+}
+
+// Reporter logs each lifecycle event reported by a services.Runner, a services.ResourceServiceRetrier or a
+// services.ServerSupervisor.
+//
+// Every entry has the service name and kind (check KindResource, KindServer and KindConfigurable). The entries of the
+// After events also have the duration since the Before event and, when it failed, the error.
+type Reporter struct {
+	logger      *slog.Logger
+	beforeLevel slog.Level
+	level       slog.Level
+	errorLevel  slog.Level
+
+	mutex     sync.Mutex
+	startedAt map[eventKey]time.Time
+}
+
+// Option configures a Reporter created by New.
+type Option = func(*Reporter)
+
+// WithBeforeLevel is an Option that sets the level of the Before events (starting, stopping, loading, reloading). The default is
+// `slog.LevelDebug`.
+func WithBeforeLevel(level slog.Level) Option {
+	return func(reporter *Reporter) {
+		reporter.beforeLevel = level
+	}
+}
+
+// WithLevel is an Option that sets the level of the successful After events, the retries, the restarts and the
+// signals received. The default is `slog.LevelInfo`.
+func WithLevel(level slog.Level) Option {
+	return func(reporter *Reporter) {
+		reporter.level = level
+	}
+}
+
+// WithErrorLevel is an Option that sets the level of the failed After events. The default is `slog.LevelError`.
+func WithErrorLevel(level slog.Level) Option {
+	return func(reporter *Reporter) {
+		reporter.errorLevel = level
+	}
+}
+
+// New creates a new Reporter that logs to the given logger. If logger is nil, `slog.Default()` is used.
+func New(logger *slog.Logger, opts ...Option) *Reporter {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	reporter := &Reporter{
+		logger:      logger,
+		beforeLevel: slog.LevelDebug,
+		level:       slog.LevelInfo,
+		errorLevel:  slog.LevelError,
+		startedAt:   make(map[eventKey]time.Time),
+	}
+	for _, opt := range opts {
+		opt(reporter)
+	}
+	return reporter
+}
+
+// BeforeStart logs that the service is starting.
+func (reporter *Reporter) BeforeStart(ctx context.Context, service services.Service) {
+	reporter.before(ctx, "start", service, "starting service", serviceAttrs(service)...)
+}
+
+// AfterStart logs that the service started, or failed starting.
+func (reporter *Reporter) AfterStart(ctx context.Context, service services.Service, err error) {
+	reporter.after(ctx, "start", service, "service started", "service failed starting", err, serviceAttrs(service)...)
+}
+
+// BeforeStop logs that the service is stopping.
+func (reporter *Reporter) BeforeStop(ctx context.Context, service services.Service) {
+	reporter.before(ctx, "stop", service, "stopping service", serviceAttrs(service)...)
+}
+
+// AfterStop logs that the service stopped, or failed stopping.
+func (reporter *Reporter) AfterStop(ctx context.Context, service services.Service, err error) {
+	reporter.after(ctx, "stop", service, "service stopped", "service failed stopping", err, serviceAttrs(service)...)
+}
+
+// BeforeLoad logs that the configuration of the service is loading.
+func (reporter *Reporter) BeforeLoad(ctx context.Context, configurable services.Configurable) {
+	reporter.before(ctx, "load", configurable, "loading service configuration", configurableAttrs(configurable)...)
+}
+
+// AfterLoad logs that the configuration of the service was loaded, or failed loading.
+func (reporter *Reporter) AfterLoad(ctx context.Context, configurable services.Configurable, err error) {
+	reporter.after(ctx, "load", configurable, "service configuration loaded", "service failed loading configuration", err, configurableAttrs(configurable)...)
+}
+
+// BeforeReload logs that the configuration of the service is reloading.
+func (reporter *Reporter) BeforeReload(ctx context.Context, service services.Service) {
+	reporter.before(ctx, "reload", service, "reloading service configuration", serviceAttrs(service)...)
+}
+
+// AfterReload logs that the configuration of the service was reloaded, or failed reloading.
+func (reporter *Reporter) AfterReload(ctx context.Context, service services.Service, err error) {
+	reporter.after(ctx, "reload", service, "service configuration reloaded", "service failed reloading configuration", err, serviceAttrs(service)...)
+}
+
+// SignalReceived logs the signal received.
+func (reporter *Reporter) SignalReceived(sig os.Signal) {
+	reporter.logger.LogAttrs(context.Background(), reporter.level, "signal received", slog.String("signal", sig.String()))
+}
+
+// BeforeRetry logs each attempt of starting a service wrapped by a services.ResourceServiceRetrier.
+func (reporter *Reporter) BeforeRetry(ctx context.Context, service services.Service, attempt int) {
+	attrs := append(serviceAttrs(service), slog.Int("attempt", attempt))
+	reporter.logger.LogAttrs(ctx, reporter.level, "starting service attempt", attrs...)
+}
+
+// AfterRetry logs the failure of an attempt of starting a service wrapped by a services.ResourceServiceRetrier, with
+// how long it will wait for the next attempt. Successful attempts are not logged, since AfterStart already is.
+func (reporter *Reporter) AfterRetry(ctx context.Context, service services.Service, attempt int, err error, next time.Duration) {
+	if err == nil {
+		return
+	}
+	attrs := append(serviceAttrs(service), slog.Int("attempt", attempt), slog.String("error", err.Error()))
+	if next == backoff.Stop {
+		reporter.logger.LogAttrs(ctx, reporter.errorLevel, "service attempt failed, giving up", attrs...)
+		return
+	}
+	attrs = append(attrs, slog.Duration("next", next))
+	reporter.logger.LogAttrs(ctx, reporter.level, "service attempt failed", attrs...)
+}
+
+// BeforeRestart logs that a services.ServerSupervisor is restarting a server.
+func (reporter *Reporter) BeforeRestart(ctx context.Context, service services.Service, restarts int, err error) {
+	attrs := append(serviceAttrs(service), slog.Int("restarts", restarts))
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	reporter.logger.LogAttrs(ctx, reporter.level, "restarting service", attrs...)
+}
+
+// PanicRecovered logs a panic of a service recovered by the services.Runner, with its stack trace.
+func (reporter *Reporter) PanicRecovered(ctx context.Context, err *services.PanicError) {
+	attrs := append(serviceAttrs(err.Service),
+		slog.String("operation", err.Operation),
+		slog.String("panic", fmt.Sprint(err.Value)),
+		slog.String("stack", string(err.Stack)),
+	)
+	reporter.logger.LogAttrs(ctx, reporter.errorLevel, "service panicked", attrs...)
+}
+
+// BeforeShutdownPhase logs that a shutdown phase is starting.
+func (reporter *Reporter) BeforeShutdownPhase(ctx context.Context, phase string) {
+	reporter.before(ctx, "phase", phase, "starting shutdown phase", slog.String("phase", phase))
+}
+
+// AfterShutdownPhase logs that a shutdown phase finished, or that some of its servers failed.
+func (reporter *Reporter) AfterShutdownPhase(ctx context.Context, phase string, err error) {
+	reporter.after(ctx, "phase", phase, "shutdown phase finished", "shutdown phase failed", err, slog.String("phase", phase))
+}
+
+// before logs a Before event, keeping when it happened.
+func (reporter *Reporter) before(ctx context.Context, action string, subject interface{}, msg string, attrs ...slog.Attr) {
+	reporter.mutex.Lock()
+	reporter.startedAt[newEventKey(action, subject)] = time.Now()
+	reporter.mutex.Unlock()
+
+	reporter.logger.LogAttrs(ctx, reporter.beforeLevel, msg, attrs...)
+}
+
+// after logs an After event with the duration since its Before event. If there was no Before event, the duration is
+// omitted.
+func (reporter *Reporter) after(ctx context.Context, action string, subject interface{}, msg, errMsg string, err error, attrs ...slog.Attr) {
+	key := newEventKey(action, subject)
+	reporter.mutex.Lock()
+	startedAt, ok := reporter.startedAt[key]
+	delete(reporter.startedAt, key)
+	reporter.mutex.Unlock()
+
+	if ok {
+		attrs = append(attrs, slog.Duration("duration", time.Since(startedAt)))
+	}
+
+	level := reporter.level
+	if err != nil {
+		level = reporter.errorLevel
+		msg = errMsg
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	reporter.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+func serviceAttrs(service services.Service) []slog.Attr {
+	return []slog.Attr{
+		slog.String("service", service.Name()),
+		slog.String("kind", kindOf(service)),
+	}
+}
+
+func configurableAttrs(configurable services.Configurable) []slog.Attr {
+	name := fmt.Sprintf("%T", configurable)
+	if service, ok := configurable.(services.Service); ok {
+		name = service.Name()
+	}
+	return []slog.Attr{
+		slog.String("service", name),
+		slog.String("kind", KindConfigurable),
+	}
+}
+
+// kindOf returns the kind of the given service. Services that are neither a Resource nor a Server have no kind.
+func kindOf(service services.Service) string {
+	switch service.(type) {
+	case services.Server:
+		return KindServer
+	case services.Resource:
+		return KindResource
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,13 @@
+package slogreporter_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestSlogReporter(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Slog Reporter Tests")
+}
@@ -0,0 +1,254 @@
+package slogreporter_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/setare/go-services"
+	"github.com/setare/go-services/slogreporter"
+)
+
+type resource struct {
+	name string
+}
+
+func (r *resource) Name() string                { return r.name }
+func (r *resource) Start(context.Context) error { return nil }
+func (r *resource) Stop(context.Context) error  { return nil }
+func (r *resource) Load(context.Context) error  { return nil }
+
+// taggedResource is a resource whose type is not comparable, since it holds a slice.
+type taggedResource struct {
+	name string
+	tags []string
+}
+
+func (r taggedResource) Name() string                { return r.name }
+func (r taggedResource) Start(context.Context) error { return nil }
+func (r taggedResource) Stop(context.Context) error  { return nil }
+
+type server struct {
+	name string
+}
+
+func (s *server) Name() string                 { return s.name }
+func (s *server) Listen(context.Context) error { return nil }
+func (s *server) Close(context.Context) error  { return nil }
+
+// entries parses the JSON entries written to the buffer.
+func entries(buf *bytes.Buffer) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0)
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		entry := make(map[string]interface{})
+		Expect(json.Unmarshal([]byte(line), &entry)).To(Succeed())
+		delete(entry, "time")
+		result = append(result, entry)
+	}
+	return result
+}
+
+var _ = Describe("Reporter", func() {
+	var _ services.RetrierReporter = &slogreporter.Reporter{}
+	var _ services.AttemptReporter = &slogreporter.Reporter{}
+	var _ services.SupervisorReporter = &slogreporter.Reporter{}
+	var _ services.PhaseReporter = &slogreporter.Reporter{}
+	var _ services.PanicReporter = &slogreporter.Reporter{}
+	var _ services.ReloadReporter = &slogreporter.Reporter{}
+
+	var (
+		buf    *bytes.Buffer
+		logger *slog.Logger
+	)
+
+	BeforeEach(func() {
+		buf = bytes.NewBuffer(nil)
+		logger = slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	})
+
+	It("should log the start of a Resource with its duration", func() {
+		ctx := context.TODO()
+		reporter := slogreporter.New(logger)
+		serviceA := &resource{name: "Service A"}
+
+		reporter.BeforeStart(ctx, serviceA)
+		time.Sleep(time.Millisecond * 20)
+		reporter.AfterStart(ctx, serviceA, nil)
+
+		logged := entries(buf)
+		Expect(logged).To(HaveLen(2))
+		Expect(logged[0]).To(Equal(map[string]interface{}{
+			"level":   "DEBUG",
+			"msg":     "starting service",
+			"service": "Service A",
+			"kind":    slogreporter.KindResource,
+		}))
+		Expect(logged[1]).To(HaveKeyWithValue("level", "INFO"))
+		Expect(logged[1]).To(HaveKeyWithValue("msg", "service started"))
+		Expect(logged[1]).To(HaveKeyWithValue("service", "Service A"))
+		Expect(logged[1]).To(HaveKeyWithValue("kind", slogreporter.KindResource))
+		Expect(logged[1]["duration"]).To(BeNumerically(">=", float64(time.Millisecond*20)))
+		Expect(logged[1]).ToNot(HaveKey("error"))
+	})
+
+	It("should log the duration of services whose types are not comparable", func() {
+		ctx := context.TODO()
+		reporter := slogreporter.New(logger)
+		serviceA := taggedResource{name: "Service A", tags: []string{"db"}}
+
+		reporter.BeforeStart(ctx, serviceA)
+		reporter.AfterStart(ctx, serviceA, nil)
+
+		logged := entries(buf)
+		Expect(logged).To(HaveLen(2))
+		Expect(logged[1]).To(HaveKeyWithValue("service", "Service A"))
+		Expect(logged[1]).To(HaveKey("duration"))
+	})
+
+	It("should log the failures with the error level", func() {
+		ctx := context.TODO()
+		reporter := slogreporter.New(logger, slogreporter.WithErrorLevel(slog.LevelWarn))
+		serverA := &server{name: "Server A"}
+
+		reporter.BeforeStop(ctx, serverA)
+		reporter.AfterStop(ctx, serverA, errors.New("random error"))
+
+		logged := entries(buf)
+		Expect(logged).To(HaveLen(2))
+		Expect(logged[1]).To(HaveKeyWithValue("level", "WARN"))
+		Expect(logged[1]).To(HaveKeyWithValue("msg", "service failed stopping"))
+		Expect(logged[1]).To(HaveKeyWithValue("kind", slogreporter.KindServer))
+		Expect(logged[1]).To(HaveKeyWithValue("error", "random error"))
+		Expect(logged[1]).To(HaveKey("duration"))
+	})
+
+	It("should log the configuration loading", func() {
+		ctx := context.TODO()
+		reporter := slogreporter.New(logger, slogreporter.WithBeforeLevel(slog.LevelInfo))
+		serviceA := &resource{name: "Service A"}
+
+		reporter.BeforeLoad(ctx, serviceA)
+		reporter.AfterLoad(ctx, serviceA, nil)
+
+		logged := entries(buf)
+		Expect(logged).To(HaveLen(2))
+		Expect(logged[0]).To(HaveKeyWithValue("level", "INFO"))
+		Expect(logged[0]).To(HaveKeyWithValue("msg", "loading service configuration"))
+		Expect(logged[0]).To(HaveKeyWithValue("kind", slogreporter.KindConfigurable))
+		Expect(logged[1]).To(HaveKeyWithValue("msg", "service configuration loaded"))
+		Expect(logged[1]).To(HaveKeyWithValue("service", "Service A"))
+	})
+
+	It("should log the configuration reloading", func() {
+		ctx := context.TODO()
+		reporter := slogreporter.New(logger)
+		serviceA := &resource{name: "Service A"}
+
+		reporter.BeforeReload(ctx, serviceA)
+		reporter.AfterReload(ctx, serviceA, errors.New("invalid configuration"))
+
+		logged := entries(buf)
+		Expect(logged).To(HaveLen(2))
+		Expect(logged[0]).To(HaveKeyWithValue("level", "DEBUG"))
+		Expect(logged[0]).To(HaveKeyWithValue("msg", "reloading service configuration"))
+		Expect(logged[1]).To(HaveKeyWithValue("level", "ERROR"))
+		Expect(logged[1]).To(HaveKeyWithValue("msg", "service failed reloading configuration"))
+		Expect(logged[1]).To(HaveKeyWithValue("service", "Service A"))
+		Expect(logged[1]).To(HaveKeyWithValue("kind", slogreporter.KindResource))
+		Expect(logged[1]).To(HaveKeyWithValue("error", "invalid configuration"))
+		Expect(logged[1]).To(HaveKey("duration"))
+	})
+
+	It("should log the retries, restarts, signals and panics", func() {
+		ctx := context.TODO()
+		reporter := slogreporter.New(logger, slogreporter.WithLevel(slog.LevelWarn))
+		serviceA := &resource{name: "Service A"}
+		serverA := &server{name: "Server A"}
+
+		reporter.BeforeRetry(ctx, serviceA, 2)
+		reporter.AfterRetry(ctx, serviceA, 2, errors.New("random error"), time.Second)
+		reporter.BeforeRestart(ctx, serverA, 1, errors.New("connection reset"))
+		reporter.SignalReceived(os.Interrupt)
+		reporter.PanicRecovered(ctx, &services.PanicError{
+			Service:   serverA,
+			Operation: "listen",
+			Value:     "random panic",
+			Stack:     []byte("goroutine 1"),
+		})
+
+		Expect(entries(buf)).To(Equal([]map[string]interface{}{
+			{
+				"level":   "WARN",
+				"msg":     "starting service attempt",
+				"service": "Service A",
+				"kind":    slogreporter.KindResource,
+				"attempt": float64(2),
+			},
+			{
+				"level":   "WARN",
+				"msg":     "service attempt failed",
+				"service": "Service A",
+				"kind":    slogreporter.KindResource,
+				"attempt": float64(2),
+				"error":   "random error",
+				"next":    float64(time.Second),
+			},
+			{
+				"level":    "WARN",
+				"msg":      "restarting service",
+				"service":  "Server A",
+				"kind":     slogreporter.KindServer,
+				"restarts": float64(1),
+				"error":    "connection reset",
+			},
+			{
+				"level":  "WARN",
+				"msg":    "signal received",
+				"signal": "interrupt",
+			},
+			{
+				"level":     "ERROR",
+				"msg":       "service panicked",
+				"service":   "Server A",
+				"kind":      slogreporter.KindServer,
+				"operation": "listen",
+				"panic":     "random panic",
+				"stack":     "goroutine 1",
+			},
+		}))
+	})
+
+	It("should log the lifecycle of a Runner", func() {
+		ctx := context.TODO()
+		reporter := slogreporter.New(logger)
+		serviceA := &resource{name: "Service A"}
+
+		runner := services.NewRunner(services.WithReporter(reporter))
+		Expect(runner.Run(ctx, serviceA)).To(Succeed())
+		Expect(runner.Finish(ctx)).To(Succeed())
+
+		msgs := make([]interface{}, 0)
+		for _, entry := range entries(buf) {
+			msgs = append(msgs, entry["msg"])
+		}
+		Expect(msgs).To(Equal([]interface{}{
+			"loading service configuration",
+			"service configuration loaded",
+			"starting service",
+			"service started",
+			"stopping service",
+			"service stopped",
+		}))
+	})
+})
@@ -0,0 +1,275 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// ServiceState is the lifecycle state of a service managed by a Runner.
+type ServiceState int
+
+const (
+	// StateIdle is the state of a service that was never started.
+	StateIdle ServiceState = iota
+	// StateLoading is the state of a Configurable service while its configuration is loaded.
+	StateLoading
+	// StateStarting is the state of a service while it is started (Resource.Start or before Server.Listen is called).
+	StateStarting
+	// StateRunning is the state of a Resource that was started, or a Server that is listening.
+	StateRunning
+	// StateStopping is the state of a service while it is stopped (Resource.Stop or Server.Close).
+	StateStopping
+	// StateStopped is the state of a service that was stopped, or a Server whose Listen returned without errors.
+	StateStopped
+	// StateFailed is the state of a service that failed loading, starting, listening or stopping.
+	StateFailed
+)
+
+// Deprecated: these are kept for compatibility, use the ServiceState constants instead.
+const (
+	ListenStateIdle      = StateIdle
+	ListenStateStarting  = StateStarting
+	ListenStateListening = StateRunning
+	ListenStateClosing   = StateStopping
+	ListenStateClosed    = StateStopped
+)
+
+var serviceStateNames = map[ServiceState]string{
+	StateIdle:     "idle",
+	StateLoading:  "loading",
+	StateStarting: "starting",
+	StateRunning:  "running",
+	StateStopping: "stopping",
+	StateStopped:  "stopped",
+	StateFailed:   "failed",
+}
+
+func (state ServiceState) String() string {
+	if name, ok := serviceStateNames[state]; ok {
+		return name
+	}
+	return fmt.Sprintf("ServiceState(%d)", int(state))
+}
+
+// validTransitions maps each state to the states it can transition to.
+var validTransitions = map[ServiceState][]ServiceState{
+	StateIdle:     {StateLoading, StateStarting},
+	StateLoading:  {StateStarting, StateFailed},
+	StateStarting: {StateRunning, StateStopping, StateFailed},
+	StateRunning:  {StateStopping, StateStopped, StateFailed},
+	StateStopping: {StateStopped, StateFailed},
+	StateStopped:  {StateLoading, StateStarting},
+	StateFailed:   {StateLoading, StateStarting, StateStopping},
+}
+
+// canTransition checks if a service can go from one state to the other.
+func canTransition(from, to ServiceState) bool {
+	for _, state := range validTransitions[from] {
+		if state == to {
+			return true
+		}
+	}
+	return false
+}
+
+// StateTransitionError is returned when a service is asked to go to a state that cannot be reached from its current
+// state. For example, when Runner.Run is called with a Resource that is already running. It matches
+// ErrInvalidStateTransition when using `errors.Is`.
+type StateTransitionError struct {
+	Service Service
+	From    ServiceState
+	To      ServiceState
+}
+
+func (err *StateTransitionError) Error() string {
+	return fmt.Sprintf("%s: %s cannot go from %s to %s", ErrInvalidStateTransition, serviceName(err.Service), err.From, err.To)
+}
+
+// Is implements the `errors.Is` support.
+func (err *StateTransitionError) Is(target error) bool {
+	return target == ErrInvalidStateTransition
+}
+
+// ServiceStatus is the snapshot of the lifecycle state of a service managed by a Runner.
+type ServiceStatus struct {
+	Name    string
+	Service Service
+	State   ServiceState
+	// Since is when the service entered the current state.
+	Since time.Time
+	// StartedAt is when the service was last running. It is zero if the service never ran.
+	StartedAt time.Time
+	// Err is the error that made the service fail, if the State is StateFailed.
+	Err error
+	// Retries is how many times the last start of the service was retried (check Retrier). It is always zero for the
+	// services that are not wrapped by a retrier.
+	Retries int
+}
+
+// retryCounter describes a service that counts its retries, like ResourceServiceRetrier and ServerServiceRetrier.
+type retryCounter interface {
+	Retries() int
+}
+
+// serviceState is the lifecycle state of a service kept by the Runner.
+type serviceState struct {
+	state     ServiceState
+	since     time.Time
+	startedAt time.Time
+	err       error
+}
+
+// transition moves the given service to another state. If the transition is not valid, a StateTransitionError is
+// returned and the state is kept.
+func (r *Runner) transition(service Service, to ServiceState, err error) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.transitionLocked(service, to, err)
+}
+
+// transitionLocked is the same as transition, but it must be called holding the mutex.
+func (r *Runner) transitionLocked(service Service, to ServiceState, err error) error {
+	status, ok := r.states[service]
+	if !ok {
+		r.registerServices([]Service{service})
+		status = r.states[service]
+	}
+	if !canTransition(status.state, to) {
+		return &StateTransitionError{
+			Service: service,
+			From:    status.state,
+			To:      to,
+		}
+	}
+	status.state = to
+	status.since = time.Now()
+	if to == StateRunning {
+		status.startedAt = status.since
+	}
+	status.err = nil
+	if to == StateFailed {
+		status.err = err
+	}
+	return nil
+}
+
+// checkNotHeld fails with a StateTransitionError when one of the given services is a Resource still held by the Runner.
+// A Resource whose Stop failed is kept (and failed) until a Finish stops it, so it cannot be started again before
+// that. It must be called holding the mutex.
+func (r *Runner) checkNotHeld(services []Service) error {
+	for _, service := range services {
+		for _, resource := range r.resourceServices {
+			if Service(resource) != service {
+				continue
+			}
+			return &StateTransitionError{
+				Service: service,
+				From:    r.states[service].state,
+				To:      StateStarting,
+			}
+		}
+	}
+	return nil
+}
+
+// checkComparable fails with ErrServiceNotComparable when the service cannot be used as a map key. The Runner keeps
+// the state of the services indexed by them.
+func checkComparable(service Service) error {
+	if service == nil || reflect.ValueOf(service).Comparable() {
+		return nil
+	}
+	return fmt.Errorf("%w: %s (%T)", ErrServiceNotComparable, service.Name(), service)
+}
+
+// ServiceKey returns a value that identifies the service and can be used as a map key. It is the service itself,
+// unless its type is not comparable (e.g. a struct holding a slice): then, it is made of the type and the name of the
+// service. It is meant for the reporters, which can be given services that were not given to a Runner (e.g. the
+// service of a Retrier).
+func ServiceKey(service Service) interface{} {
+	if service == nil || reflect.ValueOf(service).Comparable() {
+		return service
+	}
+	return uncomparableServiceKey{reflect.TypeOf(service), service.Name()}
+}
+
+type uncomparableServiceKey struct {
+	t    reflect.Type
+	name string
+}
+
+// registerServices adds the given services, that are not registered yet, in the idle state. It must be called holding
+// the mutex.
+func (r *Runner) registerServices(services []Service) {
+	for _, service := range services {
+		if _, ok := r.states[service]; ok {
+			continue
+		}
+		r.states[service] = &serviceState{
+			state: StateIdle,
+			since: time.Now(),
+		}
+		r.stateOrder = append(r.stateOrder, service)
+	}
+}
+
+// transitionFrom is the same as transition, but the service is moved only if it is in the given state. It returns
+// whether the service was moved.
+func (r *Runner) transitionFrom(service Service, from, to ServiceState, err error) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if status, ok := r.states[service]; !ok || status.state != from {
+		return false
+	}
+	return r.transitionLocked(service, to, err) == nil
+}
+
+// listenReturned moves a running server to stopped, or failed, when its Listen returns by itself. If the server is
+// being closed, the state is left to Runner.stopServer.
+func (r *Runner) listenReturned(server Server, err error) {
+	if err != nil && err != context.Canceled {
+		r.transitionFrom(server, StateRunning, StateFailed, err)
+		return
+	}
+	r.transitionFrom(server, StateRunning, StateStopped, nil)
+}
+
+// State returns the current state of the service with the given name. If the Runner never managed a service with that
+// name, false is returned.
+func (r *Runner) State(name string) (ServiceState, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, service := range r.stateOrder {
+		if serviceName(service) == name {
+			return r.states[service].state, true
+		}
+	}
+	return StateIdle, false
+}
+
+// Services returns a snapshot of the lifecycle state of all services managed by the Runner, in the order they were
+// first given to Runner.Run.
+func (r *Runner) Services() []ServiceStatus {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	result := make([]ServiceStatus, len(r.stateOrder))
+	for idx, service := range r.stateOrder {
+		status := r.states[service]
+		result[idx] = ServiceStatus{
+			Name:      serviceName(service),
+			Service:   service,
+			State:     status.state,
+			Since:     status.since,
+			StartedAt: status.startedAt,
+			Err:       status.err,
+		}
+		if counter, ok := service.(retryCounter); ok {
+			result[idx].Retries = counter.Retries()
+		}
+	}
+	return result
+}
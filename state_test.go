@@ -0,0 +1,237 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/setare/go-services"
+)
+
+// taggedResource is a Resource whose type is not comparable, since it holds a slice.
+type taggedResource struct {
+	name      string
+	tags      []string
+	dependsOn []services.Service
+}
+
+func (r taggedResource) Name() string                  { return r.name }
+func (r taggedResource) Start(context.Context) error   { return nil }
+func (r taggedResource) Stop(context.Context) error    { return nil }
+func (r taggedResource) DependsOn() []services.Service { return r.dependsOn }
+
+var _ = Describe("State", func() {
+	stateOf := func(runner *services.Runner, name string) func() services.ServiceState {
+		return func() services.ServiceState {
+			state, _ := runner.State(name)
+			return state
+		}
+	}
+
+	It("should track the lifecycle of Resource instances", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		serviceA := NewMockResource(ctrl)
+		serviceA.EXPECT().Name().Return("Service A").AnyTimes()
+		serviceB := NewMockResource(ctrl)
+		serviceB.EXPECT().Name().Return("Service B").AnyTimes()
+
+		wantErr := errors.New("random error")
+		serviceA.EXPECT().Start(gomock.Any())
+		serviceB.EXPECT().Start(gomock.Any()).Return(wantErr)
+		serviceA.EXPECT().Stop(gomock.Any())
+
+		runner := services.NewRunner()
+		_, ok := runner.State("Service A")
+		Expect(ok).To(BeFalse())
+
+		Expect(runner.Run(ctx, serviceA, serviceB)).To(MatchError(wantErr))
+		Expect(stateOf(runner, "Service A")()).To(Equal(services.StateRunning))
+
+		statuses := runner.Services()
+		Expect(statuses).To(HaveLen(2))
+		Expect(statuses[0].Name).To(Equal("Service A"))
+		Expect(statuses[0].Service).To(Equal(serviceA))
+		Expect(statuses[0].State).To(Equal(services.StateRunning))
+		Expect(statuses[1].Name).To(Equal("Service B"))
+		Expect(statuses[1].State).To(Equal(services.StateFailed))
+		Expect(statuses[1].Err).To(MatchError(wantErr))
+
+		Expect(runner.Finish(ctx)).To(Succeed())
+		Expect(stateOf(runner, "Service A")()).To(Equal(services.StateStopped))
+	})
+
+	It("should reject starting a Resource that is already running", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		serviceA := NewMockResource(ctrl)
+		serviceA.EXPECT().Name().Return("Service A").AnyTimes()
+		serviceA.EXPECT().Start(gomock.Any())
+
+		runner := services.NewRunner()
+		Expect(runner.Run(ctx, serviceA)).To(Succeed())
+
+		err := runner.Run(ctx, serviceA)
+		Expect(err).To(MatchError(services.ErrInvalidStateTransition))
+		Expect(err.Error()).To(Equal("invalid state transition: Service A cannot go from running to starting"))
+
+		var transitionErr *services.StateTransitionError
+		Expect(errors.As(err, &transitionErr)).To(BeTrue())
+		Expect(transitionErr.Service).To(Equal(serviceA))
+		Expect(transitionErr.From).To(Equal(services.StateRunning))
+		Expect(transitionErr.To).To(Equal(services.StateStarting))
+	})
+
+	It("should reject starting a Resource that failed to stop until it is finished", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		serviceA := NewMockResource(ctrl)
+		serviceA.EXPECT().Name().Return("Service A").AnyTimes()
+		wantErr := errors.New("stop failed")
+		gomock.InOrder(
+			serviceA.EXPECT().Start(gomock.Any()),
+			serviceA.EXPECT().Stop(gomock.Any()).Return(wantErr),
+			serviceA.EXPECT().Stop(gomock.Any()),
+		)
+
+		runner := services.NewRunner()
+		Expect(runner.Run(ctx, serviceA)).To(Succeed())
+		Expect(runner.Finish(ctx)).To(MatchError(wantErr))
+		Expect(stateOf(runner, "Service A")()).To(Equal(services.StateFailed))
+
+		err := runner.Run(ctx, serviceA)
+		Expect(err).To(MatchError(services.ErrInvalidStateTransition))
+		Expect(err.Error()).To(Equal("invalid state transition: Service A cannot go from failed to starting"))
+
+		// The Resource is stopped only once.
+		Expect(runner.Finish(ctx)).To(Succeed())
+		Expect(stateOf(runner, "Service A")()).To(Equal(services.StateStopped))
+	})
+
+	It("should reject services whose types are not comparable", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		serviceA := NewMockResource(ctrl)
+		serviceA.EXPECT().Name().Return("Service A").AnyTimes()
+
+		runner := services.NewRunner()
+		err := runner.Run(ctx, serviceA, taggedResource{name: "Service B", tags: []string{"db"}})
+		Expect(err).To(MatchError(services.ErrServiceNotComparable))
+		Expect(err.Error()).To(Equal("service is not comparable: Service B (services_test.taggedResource)"))
+
+		dependent := &taggedResource{name: "Service C", dependsOn: []services.Service{taggedResource{name: "Service B"}}}
+		Expect(runner.Run(ctx, serviceA, dependent)).To(MatchError(services.ErrServiceNotComparable))
+		Expect(runner.Services()).To(BeEmpty())
+	})
+
+	It("should identify services whose types are not comparable by their type and name", func() {
+		serviceA := &taggedResource{name: "Service A"}
+		Expect(services.ServiceKey(serviceA)).To(BeIdenticalTo(serviceA))
+
+		keys := map[interface{}]bool{
+			services.ServiceKey(taggedResource{name: "Service A", tags: []string{"a"}}): true,
+		}
+		Expect(keys).To(HaveKey(services.ServiceKey(taggedResource{name: "Service A", tags: []string{"b"}})))
+		Expect(keys).ToNot(HaveKey(services.ServiceKey(taggedResource{name: "Service B"})))
+	})
+
+	It("should track the lifecycle of Configurable services", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		serviceA := &struct {
+			*MockResource
+			*MockConfigurable
+		}{
+			MockResource:     NewMockResource(ctrl),
+			MockConfigurable: NewMockConfigurable(ctrl),
+		}
+		serviceA.MockResource.EXPECT().Name().Return("Service A").AnyTimes()
+
+		runner := services.NewRunner()
+		gomock.InOrder(
+			serviceA.MockConfigurable.EXPECT().Load(gomock.Any()).Do(func(context.Context) {
+				defer GinkgoRecover()
+				Expect(stateOf(runner, "Service A")()).To(Equal(services.StateLoading))
+			}),
+			serviceA.MockResource.EXPECT().Start(gomock.Any()).Do(func(context.Context) {
+				defer GinkgoRecover()
+				Expect(stateOf(runner, "Service A")()).To(Equal(services.StateStarting))
+			}),
+		)
+
+		Expect(runner.Run(ctx, serviceA)).To(Succeed())
+		Expect(stateOf(runner, "Service A")()).To(Equal(services.StateRunning))
+	})
+
+	It("should track the lifecycle of Server instances", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx, cancelFunc := context.WithCancel(context.TODO())
+		defer cancelFunc()
+
+		closed := make(chan struct{})
+		serverA := NewMockServer(ctrl)
+		serverA.EXPECT().Name().Return("Server A").AnyTimes()
+		serverA.EXPECT().Listen(gomock.Any()).Do(func(context.Context) {
+			<-closed
+		})
+		serverA.EXPECT().Close(gomock.Any()).Do(func(context.Context) {
+			time.Sleep(time.Millisecond * 100)
+			close(closed)
+		})
+
+		runner := services.NewRunner()
+
+		runErr := make(chan error, 1)
+		go func() {
+			runErr <- runner.Run(ctx, serverA)
+		}()
+
+		Eventually(stateOf(runner, "Server A")).Should(Equal(services.StateRunning))
+		cancelFunc()
+		Eventually(stateOf(runner, "Server A")).Should(Equal(services.StateStopping))
+		Eventually(runErr).Should(Receive(MatchError(context.Canceled)))
+		Expect(stateOf(runner, "Server A")()).To(Equal(services.StateStopped))
+	})
+
+	It("should fail a Server whose Listen fails", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		wantErr := errors.New("address already in use")
+		serverA := NewMockServer(ctrl)
+		serverA.EXPECT().Name().Return("Server A").AnyTimes()
+		serverA.EXPECT().Listen(gomock.Any()).Return(wantErr)
+		serverA.EXPECT().Close(gomock.Any())
+
+		runner := services.NewRunner()
+		Expect(runner.Run(ctx, serverA)).To(MatchError(wantErr))
+
+		statuses := runner.Services()
+		Expect(statuses).To(HaveLen(1))
+		Expect(statuses[0].State).To(Equal(services.StateFailed))
+		Expect(statuses[0].Err).To(MatchError(wantErr))
+	})
+})
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultSubscriptionBuffer is how many events a Subscription holds, by default, before dropping new ones.
+const DefaultSubscriptionBuffer = 256
+
+// Subscription receives the lifecycle events of a Runner (check Runner.Events). The events are buffered: when the
+// buffer is full, new events are dropped instead of blocking the Runner. So, a slow subscriber misses events, but it
+// never delays the services.
+type Subscription struct {
+	broker  *eventBroker
+	ch      chan Event
+	dropped atomic.Int64
+}
+
+// SubscriptionOption configures a Subscription created by Runner.Events.
+type SubscriptionOption = func(*Subscription)
+
+// WithSubscriptionBuffer is a SubscriptionOption that sets how many events the Subscription holds before dropping new
+// ones. The default is DefaultSubscriptionBuffer.
+func WithSubscriptionBuffer(size int) SubscriptionOption {
+	return func(subscription *Subscription) {
+		subscription.ch = make(chan Event, size)
+	}
+}
+
+// Events returns the channel that receives the events. It is closed by Close.
+func (subscription *Subscription) Events() <-chan Event {
+	return subscription.ch
+}
+
+// Dropped returns how many events were dropped because the buffer was full.
+func (subscription *Subscription) Dropped() int {
+	return int(subscription.dropped.Load())
+}
+
+// Close stops the delivery of events and closes the channel returned by Events. Calling it more than once is a no-op.
+func (subscription *Subscription) Close() {
+	subscription.broker.unsubscribe(subscription)
+}
+
+// send delivers the event without blocking, dropping it if the buffer is full.
+func (subscription *Subscription) send(event Event) {
+	select {
+	case subscription.ch <- event:
+	default:
+		subscription.dropped.Add(1)
+	}
+}
+
+// eventBroker is the Reporter, always notified by the Runner, that publishes the events to the subscriptions.
+type eventBroker struct {
+	*EventReporter
+	mutex         sync.RWMutex
+	subscriptions map[*Subscription]struct{}
+}
+
+func newEventBroker() *eventBroker {
+	broker := &eventBroker{
+		subscriptions: make(map[*Subscription]struct{}),
+	}
+	broker.EventReporter = &EventReporter{
+		handle: broker.publish,
+	}
+	return broker
+}
+
+func (broker *eventBroker) subscribe(opts []SubscriptionOption) *Subscription {
+	subscription := &Subscription{
+		broker: broker,
+		ch:     make(chan Event, DefaultSubscriptionBuffer),
+	}
+	for _, opt := range opts {
+		opt(subscription)
+	}
+
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+	broker.subscriptions[subscription] = struct{}{}
+	return subscription
+}
+
+func (broker *eventBroker) unsubscribe(subscription *Subscription) {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+	if _, ok := broker.subscriptions[subscription]; !ok {
+		return
+	}
+	delete(broker.subscriptions, subscription)
+	close(subscription.ch)
+}
+
+// publish sends the event to all subscriptions. The ctx given to the services by Runner.Run carries the broker, so the
+// retriers can publish their attempts (check eventBrokerFromContext).
+func (broker *eventBroker) publish(ctx context.Context, event Event) context.Context {
+	broker.mutex.RLock()
+	for subscription := range broker.subscriptions {
+		subscription.send(event)
+	}
+	broker.mutex.RUnlock()
+
+	if event.Kind == EventBeforeRun {
+		return context.WithValue(ctx, eventBrokerCtxKey{}, broker)
+	}
+	return ctx
+}
+
+type eventBrokerCtxKey struct{}
+
+// eventBrokerFromContext returns the broker of the Runner that is running the service which received the ctx, or nil.
+func eventBrokerFromContext(ctx context.Context) *eventBroker {
+	broker, _ := ctx.Value(eventBrokerCtxKey{}).(*eventBroker)
+	return broker
+}
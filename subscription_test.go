@@ -0,0 +1,183 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/golang/mock/gomock"
+	signals "github.com/jamillosantos/go-os-signals"
+	"github.com/jamillosantos/go-os-signals/signaltest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/setare/go-services"
+)
+
+// receiveAll reads the events from the subscription until its channel is closed.
+func receiveAll(subscription *services.Subscription) []services.Event {
+	events := make([]services.Event, 0)
+	for event := range subscription.Events() {
+		events = append(events, event)
+	}
+	return events
+}
+
+func eventKinds(events []services.Event) []services.EventKind {
+	kinds := make([]services.EventKind, len(events))
+	for idx, event := range events {
+		kinds[idx] = event.Kind
+	}
+	return kinds
+}
+
+var _ = Describe("Runner.Events", func() {
+	It("should publish the lifecycle events with their time", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		serviceA := NewMockResource(ctrl)
+		serviceB := NewMockResource(ctrl)
+		wantErr := errors.New("stop failed")
+		serviceA.EXPECT().Start(gomock.Any())
+		serviceB.EXPECT().Start(gomock.Any())
+		serviceB.EXPECT().Stop(gomock.Any()).Return(wantErr)
+		serviceA.EXPECT().Stop(gomock.Any())
+
+		reporter := NewMockReporter(ctrl)
+		reporter.EXPECT().BeforeStart(gomock.Any(), gomock.Any()).Times(2)
+		reporter.EXPECT().AfterStart(gomock.Any(), gomock.Any(), nil).Times(2)
+		reporter.EXPECT().BeforeStop(gomock.Any(), gomock.Any()).Times(2)
+		reporter.EXPECT().AfterStop(gomock.Any(), gomock.Any(), gomock.Any()).Times(2)
+
+		runner := services.NewRunner(services.WithReporter(reporter))
+		subscription := runner.Events()
+		Expect(runner.Run(ctx, serviceA, serviceB)).To(Succeed())
+		Expect(runner.Finish(ctx)).To(MatchError(wantErr))
+		subscription.Close()
+		subscription.Close()
+
+		events := receiveAll(subscription)
+		Expect(eventKinds(events)).To(Equal([]services.EventKind{
+			services.EventBeforeRun,
+			services.EventBeforeStart, services.EventAfterStart,
+			services.EventBeforeStart, services.EventAfterStart,
+			services.EventAfterRun,
+			services.EventBeforeFinish,
+			services.EventBeforeStop, services.EventAfterStop,
+			services.EventBeforeStop, services.EventAfterStop,
+			services.EventAfterFinish,
+		}))
+		for idx, event := range events {
+			if idx > 0 {
+				Expect(event.Time).To(BeTemporally(">=", events[idx-1].Time))
+			}
+		}
+		Expect(events[1].Service).To(Equal(serviceA))
+		Expect(events[8].Service).To(Equal(serviceB))
+		Expect(events[8].Err).To(MatchError(wantErr))
+		Expect(subscription.Dropped()).To(BeZero())
+	})
+
+	It("should publish the attempts of the retriers", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		serviceA := NewMockResource(ctrl)
+		wantErr := errors.New("connection refused")
+		gomock.InOrder(
+			serviceA.EXPECT().Start(gomock.Any()).Return(wantErr),
+			serviceA.EXPECT().Start(gomock.Any()),
+		)
+		retrier := services.Retrier().Backoff(&backoff.ZeroBackOff{}).Build(serviceA)
+
+		runner := services.NewRunner()
+		subscription := runner.Events()
+		Expect(runner.Run(ctx, retrier)).To(Succeed())
+		subscription.Close()
+
+		events := receiveAll(subscription)
+		Expect(eventKinds(events)).To(Equal([]services.EventKind{
+			services.EventBeforeRun,
+			services.EventBeforeStart,
+			services.EventBeforeRetry, services.EventAfterRetry,
+			services.EventBeforeRetry, services.EventAfterRetry,
+			services.EventAfterStart,
+			services.EventAfterRun,
+		}))
+		Expect(events[3].Service).To(Equal(serviceA))
+		Expect(events[3].Attempt).To(Equal(1))
+		Expect(events[3].Err).To(MatchError(wantErr))
+		Expect(events[5].Attempt).To(Equal(2))
+		Expect(events[5].Err).ToNot(HaveOccurred())
+	})
+
+	It("should publish the signals received", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		closed := make(chan struct{})
+		serverA := NewMockServer(ctrl)
+		serverA.EXPECT().Listen(gomock.Any()).Do(func(context.Context) {
+			<-closed
+		})
+		serverA.EXPECT().Close(gomock.Any()).Do(func(context.Context) {
+			close(closed)
+		})
+
+		listener := signaltest.NewMockListener(os.Interrupt)
+		runner := services.NewRunner(services.WithListenerBuilder(func() signals.Listener {
+			return listener
+		}))
+		subscription := runner.Events()
+		defer subscription.Close()
+
+		go func() {
+			time.Sleep(time.Millisecond * 50)
+			listener.Send(os.Interrupt)
+		}()
+
+		Expect(runner.Run(ctx, serverA)).To(MatchError(services.ErrStoppedBySignal))
+		var event services.Event
+		Eventually(subscription.Events()).Should(Receive(&event))
+		for event.Kind != services.EventSignalReceived {
+			Eventually(subscription.Events()).Should(Receive(&event))
+		}
+		Expect(event.Signal).To(Equal(os.Interrupt))
+	})
+
+	It("should not be blocked by slow subscribers", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		serviceA := NewMockResource(ctrl)
+		serviceB := NewMockResource(ctrl)
+		serviceA.EXPECT().Start(gomock.Any())
+		serviceB.EXPECT().Start(gomock.Any())
+
+		runner := services.NewRunner()
+		slow := runner.Events(services.WithSubscriptionBuffer(1))
+		defer slow.Close()
+		unread := runner.Events(services.WithSubscriptionBuffer(0))
+		defer unread.Close()
+
+		Expect(runner.Run(ctx, serviceA, serviceB)).To(Succeed())
+
+		var event services.Event
+		Expect(slow.Events()).To(Receive(&event))
+		Expect(event.Kind).To(Equal(services.EventBeforeRun))
+		Expect(slow.Events()).ToNot(Receive())
+		Expect(slow.Dropped()).To(Equal(5))
+		Expect(unread.Dropped()).To(Equal(6))
+	})
+})
@@ -0,0 +1,334 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+// RestartPolicy defines when a ServerSupervisor restarts a Server after its Listen returns.
+type RestartPolicy int
+
+const (
+	// RestartNever never restarts the Server. If it fails, the ServerSupervisor closes all other servers and fails with
+	// the same error.
+	RestartNever RestartPolicy = iota
+	// RestartOnFailure restarts the Server only when its Listen returns an error.
+	RestartOnFailure
+	// RestartAlways restarts the Server whenever its Listen returns, failing or not.
+	RestartAlways
+)
+
+// SupervisorStrategy defines which servers are restarted when one of them needs to be restarted.
+type SupervisorStrategy int
+
+const (
+	// OneForOne restarts only the Server that exited.
+	OneForOne SupervisorStrategy = iota
+	// OneForAll closes all other servers and restarts all of them together.
+	OneForAll
+)
+
+type supervisedServer struct {
+	server Server
+	policy RestartPolicy
+}
+
+// shouldRestart checks if the server should be restarted, given the error returned by its Listen.
+func (s *supervisedServer) shouldRestart(err error) bool {
+	switch s.policy {
+	case RestartAlways:
+		return true
+	case RestartOnFailure:
+		return err != nil && err != context.Canceled
+	default:
+		return false
+	}
+}
+
+// ServerSupervisor is a Server that supervises a group of Server instances, restarting them accordingly to their
+// RestartPolicy and the SupervisorStrategy.
+//
+// If the servers are restarted more than the maximum restarts within the time window (check
+// SupervisorBuilder.MaxRestarts), the supervisor gives up: all servers are closed and Listen returns
+// ErrTooManyRestarts.
+type ServerSupervisor struct {
+	name        string
+	servers     []supervisedServer
+	strategy    SupervisorStrategy
+	maxRestarts int
+	window      time.Duration
+	backoff     backoff.BackOff
+	reporter    SupervisorReporter
+
+	mutex     sync.Mutex
+	listening bool
+	closing   bool
+	closeCh   chan struct{}
+}
+
+// SupervisorBuilder is the helper for building `ServerSupervisor`.
+type SupervisorBuilder struct {
+	servers     []supervisedServer
+	strategy    SupervisorStrategy
+	maxRestarts int
+	window      time.Duration
+	newBackoff  func() backoff.BackOff
+	reporter    SupervisorReporter
+}
+
+// Supervisor returns a new `SupervisorBuilder` instance.
+//
+// By default, it uses the OneForOne strategy, allows 3 restarts within 5 seconds and waits between restarts using an
+// exponential backoff.
+func Supervisor() *SupervisorBuilder {
+	return &SupervisorBuilder{
+		servers:     make([]supervisedServer, 0),
+		strategy:    OneForOne,
+		maxRestarts: 3,
+		window:      time.Second * 5,
+		newBackoff: func() backoff.BackOff {
+			return backoff.NewExponentialBackOff()
+		},
+	}
+}
+
+// Server adds a `Server` to be supervised with the given restart policy.
+func (builder *SupervisorBuilder) Server(server Server, policy RestartPolicy) *SupervisorBuilder {
+	builder.servers = append(builder.servers, supervisedServer{
+		server: server,
+		policy: policy,
+	})
+	return builder
+}
+
+// Strategy sets the strategy for the `ServerSupervisor`.
+func (builder *SupervisorBuilder) Strategy(value SupervisorStrategy) *SupervisorBuilder {
+	builder.strategy = value
+	return builder
+}
+
+// MaxRestarts sets how many restarts are allowed within the given window for the `ServerSupervisor`.
+func (builder *SupervisorBuilder) MaxRestarts(value int, window time.Duration) *SupervisorBuilder {
+	builder.maxRestarts = value
+	builder.window = window
+	return builder
+}
+
+// Backoff sets the backoff used to wait between restarts for the `ServerSupervisor`.
+//
+// Each supervisor built uses its own copy of the given backoff, the same way as RetrierBuilder.Backoff. Use
+// BackoffFactory to give each one its own instance of a custom backoff.
+func (builder *SupervisorBuilder) Backoff(value backoff.BackOff) *SupervisorBuilder {
+	builder.newBackoff = copyBackOff(value)
+	return builder
+}
+
+// BackoffFactory sets the function that creates the backoff of each `ServerSupervisor` built.
+func (builder *SupervisorBuilder) BackoffFactory(value func() backoff.BackOff) *SupervisorBuilder {
+	builder.newBackoff = value
+	return builder
+}
+
+// Reporter sets the reporter for the `ServerSupervisor`.
+func (builder *SupervisorBuilder) Reporter(value SupervisorReporter) *SupervisorBuilder {
+	builder.reporter = value
+	return builder
+}
+
+// Build creates a new `ServerSupervisor` with the given name.
+func (builder *SupervisorBuilder) Build(name string) *ServerSupervisor {
+	servers := make([]supervisedServer, len(builder.servers))
+	copy(servers, builder.servers)
+	return &ServerSupervisor{
+		name:        name,
+		servers:     servers,
+		strategy:    builder.strategy,
+		maxRestarts: builder.maxRestarts,
+		window:      builder.window,
+		backoff:     builder.newBackoff(),
+		reporter:    builder.reporter,
+	}
+}
+
+// Name will return a human identifiable name for this service.
+func (supervisor *ServerSupervisor) Name() string {
+	return supervisor.name
+}
+
+type serverExit struct {
+	idx int
+	err error
+}
+
+// Listen starts all supervised servers and blocks until all of them are finished, or until the supervisor gives up
+// restarting them. A server that panics is handled as if its Listen had returned a PanicError.
+//
+// If the supervisor is already listening, ErrAlreadyListening is returned.
+func (supervisor *ServerSupervisor) Listen(ctx context.Context) error {
+	supervisor.mutex.Lock()
+	if supervisor.listening {
+		supervisor.mutex.Unlock()
+		return ErrAlreadyListening
+	}
+	supervisor.listening = true
+	supervisor.closing = false
+	supervisor.closeCh = make(chan struct{})
+	closeCh := supervisor.closeCh
+	supervisor.mutex.Unlock()
+
+	defer func() {
+		supervisor.mutex.Lock()
+		supervisor.listening = false
+		supervisor.mutex.Unlock()
+	}()
+
+	supervisor.backoff.Reset()
+
+	exits := make(chan serverExit, len(supervisor.servers))
+	running := 0
+
+	start := func(idx int) {
+		running++
+		go func(idx int) {
+			server := supervisor.servers[idx].server
+			exits <- serverExit{
+				idx: idx,
+				err: recoverPanic(ctx, supervisor.reporter, server, "listen", func() error {
+					return server.Listen(ctx)
+				}),
+			}
+		}(idx)
+	}
+
+	// shutdown closes all servers that are still running waiting for them to exit.
+	shutdown := func() {
+		supervisor.closeServers(ctx)
+		for ; running > 0; running-- {
+			<-exits
+		}
+	}
+
+	for idx := range supervisor.servers {
+		start(idx)
+	}
+
+	restartCounts := make([]int, len(supervisor.servers))
+	restarts := make([]time.Time, 0)
+	for running > 0 {
+		exit := <-exits
+		running--
+
+		if supervisor.isClosing() || ctx.Err() != nil {
+			continue
+		}
+
+		server := &supervisor.servers[exit.idx]
+		if !server.shouldRestart(exit.err) {
+			if exit.err != nil && exit.err != context.Canceled {
+				shutdown()
+				return exit.err
+			}
+			continue
+		}
+
+		// Only the restarts within the window are considered. If there is none, the backoff starts over.
+		now := time.Now()
+		restarts = restartsSince(restarts, now.Add(-supervisor.window))
+		if len(restarts) == 0 {
+			supervisor.backoff.Reset()
+		}
+		restarts = append(restarts, now)
+
+		delay := supervisor.backoff.NextBackOff()
+		if len(restarts) > supervisor.maxRestarts || delay == backoff.Stop {
+			shutdown()
+			return fmt.Errorf("%w: %s: %v", ErrTooManyRestarts, server.server.Name(), exit.err)
+		}
+
+		toRestart := []int{exit.idx}
+		if supervisor.strategy == OneForAll {
+			shutdown()
+			toRestart = make([]int, len(supervisor.servers))
+			for idx := range supervisor.servers {
+				toRestart[idx] = idx
+			}
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-closeCh:
+			continue
+		case <-ctx.Done():
+			continue
+		}
+
+		supervisor.mutex.Lock()
+		if !supervisor.closing {
+			for _, idx := range toRestart {
+				restartCounts[idx]++
+				if supervisor.reporter != nil {
+					supervisor.reporter.BeforeRestart(ctx, supervisor.servers[idx].server, restartCounts[idx], exit.err)
+				}
+				start(idx)
+			}
+		}
+		supervisor.mutex.Unlock()
+	}
+
+	return ctx.Err()
+}
+
+// Close stops restarting the servers and closes all of them.
+func (supervisor *ServerSupervisor) Close(ctx context.Context) error {
+	supervisor.mutex.Lock()
+	if supervisor.closing || supervisor.closeCh == nil {
+		supervisor.mutex.Unlock()
+		return nil
+	}
+	supervisor.closing = true
+	close(supervisor.closeCh)
+	supervisor.mutex.Unlock()
+
+	return supervisor.closeServers(ctx)
+}
+
+// closeServers calls Close on all supervised servers.
+func (supervisor *ServerSupervisor) closeServers(ctx context.Context) error {
+	errs := make(MultiErrors, 0)
+	for _, server := range supervisor.servers {
+		if supervisor.reporter != nil {
+			supervisor.reporter.BeforeStop(ctx, server.server)
+		}
+		err := server.server.Close(ctx)
+		if supervisor.reporter != nil {
+			supervisor.reporter.AfterStop(ctx, server.server, err)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (supervisor *ServerSupervisor) isClosing() bool {
+	supervisor.mutex.Lock()
+	defer supervisor.mutex.Unlock()
+	return supervisor.closing
+}
+
+// restartsSince returns only the restarts that happened after the given time.
+func restartsSince(restarts []time.Time, since time.Time) []time.Time {
+	for idx, restart := range restarts {
+		if restart.After(since) {
+			return restarts[idx:]
+		}
+	}
+	return restarts[:0]
+}
@@ -0,0 +1,299 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/setare/go-services"
+	"github.com/setare/go-services/servicestest"
+)
+
+// scriptedServer is a Server whose Listen returns the given results, one per call, after a short delay (giving time to
+// the other servers to start listening). When the results are exhausted, Listen blocks until Close is called.
+type scriptedServer struct {
+	name string
+
+	mutex   sync.Mutex
+	results []error
+	listens int
+	closeCh chan struct{}
+}
+
+func newScriptedServer(name string, results ...error) *scriptedServer {
+	return &scriptedServer{
+		name:    name,
+		results: results,
+	}
+}
+
+func (s *scriptedServer) Name() string {
+	return s.name
+}
+
+func (s *scriptedServer) Listen(context.Context) error {
+	s.mutex.Lock()
+	s.listens++
+	if len(s.results) > 0 {
+		result := s.results[0]
+		s.results = s.results[1:]
+		s.mutex.Unlock()
+		time.Sleep(time.Millisecond * 10)
+		return result
+	}
+	closeCh := make(chan struct{})
+	s.closeCh = closeCh
+	s.mutex.Unlock()
+
+	<-closeCh
+	return nil
+}
+
+func (s *scriptedServer) Close(context.Context) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.closeCh != nil {
+		close(s.closeCh)
+		s.closeCh = nil
+	}
+	return nil
+}
+
+func (s *scriptedServer) Listens() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.listens
+}
+
+var _ = Describe("Supervisor", func() {
+	noDelay := func() backoff.BackOff {
+		return backoff.NewConstantBackOff(time.Millisecond)
+	}
+
+	It("should restart a failing Server", func() {
+		ctrl := createController()
+		defer ctrl.Finish()
+
+		ctx := context.TODO()
+
+		errA := errors.New("connection reset")
+		serverA := newScriptedServer("Server A", errA, errA)
+		serverB := newScriptedServer("Server B")
+
+		reporter := NewMockSupervisorReporter(ctrl)
+		gomock.InOrder(
+			reporter.EXPECT().BeforeRestart(gomock.Any(), serverA, 1, errA),
+			reporter.EXPECT().BeforeRestart(gomock.Any(), serverA, 2, errA),
+		)
+		reporter.EXPECT().BeforeStop(gomock.Any(), gomock.Any()).Times(2)
+		reporter.EXPECT().AfterStop(gomock.Any(), gomock.Any(), nil).Times(2)
+
+		supervisor := services.Supervisor().
+			Backoff(noDelay()).
+			Reporter(reporter).
+			Server(serverA, services.RestartOnFailure).
+			Server(serverB, services.RestartOnFailure).
+			Build("Supervisor")
+		Expect(supervisor.Name()).To(Equal("Supervisor"))
+
+		listenErr := make(chan error, 1)
+		go func() {
+			listenErr <- supervisor.Listen(ctx)
+		}()
+
+		Eventually(serverA.Listens).Should(Equal(3))
+		Consistently(listenErr, time.Millisecond*50).ShouldNot(Receive())
+		Expect(serverB.Listens()).To(Equal(1))
+
+		Expect(supervisor.Close(ctx)).To(Succeed())
+		Eventually(listenErr).Should(Receive(BeNil()))
+	})
+
+	It("should restart all servers when using OneForAll", func() {
+		ctx := context.TODO()
+
+		errA := errors.New("connection reset")
+		serverA := newScriptedServer("Server A", errA)
+		serverB := newScriptedServer("Server B")
+
+		supervisor := services.Supervisor().
+			Strategy(services.OneForAll).
+			Backoff(noDelay()).
+			Server(serverA, services.RestartOnFailure).
+			Server(serverB, services.RestartNever).
+			Build("Supervisor")
+
+		listenErr := make(chan error, 1)
+		go func() {
+			listenErr <- supervisor.Listen(ctx)
+		}()
+
+		Eventually(serverA.Listens).Should(Equal(2))
+		Eventually(serverB.Listens).Should(Equal(2))
+
+		Expect(supervisor.Close(ctx)).To(Succeed())
+		Eventually(listenErr).Should(Receive(BeNil()))
+	})
+
+	It("should restart a Server that finishes when using RestartAlways", func() {
+		ctx := context.TODO()
+
+		serverA := newScriptedServer("Server A", nil)
+
+		supervisor := services.Supervisor().
+			Backoff(noDelay()).
+			Server(serverA, services.RestartAlways).
+			Build("Supervisor")
+
+		listenErr := make(chan error, 1)
+		go func() {
+			listenErr <- supervisor.Listen(ctx)
+		}()
+
+		Eventually(serverA.Listens).Should(Equal(2))
+
+		Expect(supervisor.Close(ctx)).To(Succeed())
+		Eventually(listenErr).Should(Receive(BeNil()))
+	})
+
+	It("should not restart a Server when using RestartNever", func() {
+		ctx := context.TODO()
+
+		errA := errors.New("connection reset")
+		serverA := newScriptedServer("Server A", errA)
+		serverB := newScriptedServer("Server B")
+
+		supervisor := services.Supervisor().
+			Backoff(noDelay()).
+			Server(serverA, services.RestartNever).
+			Server(serverB, services.RestartNever).
+			Build("Supervisor")
+
+		Expect(supervisor.Listen(ctx)).To(MatchError(errA))
+		Expect(serverA.Listens()).To(Equal(1))
+		Expect(serverB.Listens()).To(Equal(1))
+	})
+
+	It("should give up after too many restarts", func() {
+		ctx := context.TODO()
+
+		errA := errors.New("connection reset")
+		serverA := newScriptedServer("Server A", errA, errA, errA, errA)
+		serverB := newScriptedServer("Server B")
+
+		supervisor := services.Supervisor().
+			MaxRestarts(2, time.Minute).
+			Backoff(noDelay()).
+			Server(serverA, services.RestartOnFailure).
+			Server(serverB, services.RestartOnFailure).
+			Build("Supervisor")
+
+		err := supervisor.Listen(ctx)
+		Expect(err).To(MatchError(services.ErrTooManyRestarts))
+		Expect(err.Error()).To(ContainSubstring("Server A: connection reset"))
+		Expect(serverA.Listens()).To(Equal(3))
+	})
+
+	It("should wait the backoff between restarts", func() {
+		ctx := context.TODO()
+
+		errA := errors.New("connection reset")
+		serverA := newScriptedServer("Server A", errA, errA)
+
+		supervisor := services.Supervisor().
+			Backoff(backoff.NewConstantBackOff(time.Millisecond*50)).
+			Server(serverA, services.RestartOnFailure).
+			Build("Supervisor")
+
+		listenErr := make(chan error, 1)
+		go func() {
+			listenErr <- supervisor.Listen(ctx)
+		}()
+
+		startedAt := time.Now()
+		Eventually(serverA.Listens).Should(Equal(3))
+		Expect(time.Since(startedAt)).To(BeNumerically("~", time.Millisecond*120, time.Millisecond*40))
+
+		Expect(supervisor.Close(ctx)).To(Succeed())
+		Eventually(listenErr).Should(Receive(BeNil()))
+	})
+
+	It("should reject a Listen while listening", func() {
+		ctx := context.TODO()
+
+		serverA := newScriptedServer("Server A")
+
+		supervisor := services.Supervisor().
+			Backoff(noDelay()).
+			Server(serverA, services.RestartOnFailure).
+			Build("Supervisor")
+
+		listenErr := make(chan error, 1)
+		go func() {
+			listenErr <- supervisor.Listen(ctx)
+		}()
+
+		Eventually(serverA.Listens).Should(Equal(1))
+		Expect(supervisor.Listen(ctx)).To(MatchError(services.ErrAlreadyListening))
+		Consistently(listenErr, time.Millisecond*50).ShouldNot(Receive())
+		Expect(serverA.Listens()).To(Equal(1))
+
+		Expect(supervisor.Close(ctx)).To(Succeed())
+		Eventually(listenErr).Should(Receive(BeNil()))
+	})
+
+	It("should give each supervisor built its own copy of the backoff", func() {
+		ctx := context.TODO()
+
+		errA := errors.New("connection reset")
+		serverA := newScriptedServer("Server A", errA, errA)
+
+		b := backoff.NewExponentialBackOff()
+		b.InitialInterval = time.Millisecond
+		b.RandomizationFactor = 0
+		b.Reset()
+		supervisor := services.Supervisor().
+			Backoff(b).
+			Server(serverA, services.RestartOnFailure).
+			Build("Supervisor")
+
+		listenErr := make(chan error, 1)
+		go func() {
+			listenErr <- supervisor.Listen(ctx)
+		}()
+
+		Eventually(serverA.Listens).Should(Equal(3))
+		Expect(supervisor.Close(ctx)).To(Succeed())
+		Eventually(listenErr).Should(Receive(BeNil()))
+
+		// The backoff given was not used by the supervisor.
+		Expect(b.NextBackOff()).To(Equal(time.Millisecond))
+	})
+
+	It("should create the backoff of each supervisor built with the BackoffFactory", func() {
+		created := 0
+		builder := services.Supervisor().BackoffFactory(func() backoff.BackOff {
+			created++
+			return noDelay()
+		})
+		builder.Build("Supervisor A")
+		builder.Build("Supervisor B")
+		Expect(created).To(Equal(2))
+	})
+})
+
+func TestVerifyServerSupervisor(t *testing.T) {
+	servicestest.VerifyServer(t, func() services.Server {
+		return services.Supervisor().
+			Server(servicestest.NewServer("Server A"), services.RestartOnFailure).
+			Server(servicestest.NewServer("Server B"), services.RestartOnFailure).
+			Build("Supervisor")
+	})
+}
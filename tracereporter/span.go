@@ -0,0 +1,166 @@
+package tracereporter
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// TraceID identifies a trace, following the OpenTelemetry format.
+type TraceID [16]byte
+
+// String returns the hex representation of the TraceID.
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// SpanID identifies a span, following the OpenTelemetry format.
+type SpanID [8]byte
+
+// String returns the hex representation of the SpanID.
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// IsValid checks if the SpanID is not zero. The parent of root spans is not valid.
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+// StatusCode is the status of a span, following the OpenTelemetry status codes.
+type StatusCode int
+
+const (
+	// StatusUnset is the status of spans that did not set it.
+	StatusUnset StatusCode = iota
+	// StatusOK is the status of spans whose operation succeeded.
+	StatusOK
+	// StatusError is the status of spans whose operation failed.
+	StatusError
+)
+
+// String returns the name of the status code.
+func (code StatusCode) String() string {
+	switch code {
+	case StatusOK:
+		return "Ok"
+	case StatusError:
+		return "Error"
+	default:
+		return "Unset"
+	}
+}
+
+// SpanData is an ended span, as given to the Exporter.
+type SpanData struct {
+	Name         string
+	TraceID      TraceID
+	SpanID       SpanID
+	ParentSpanID SpanID
+	StartTime    time.Time
+	EndTime      time.Time
+	Attributes   map[string]interface{}
+	Status       StatusCode
+	// StatusMessage is the error message of the spans with StatusError.
+	StatusMessage string
+}
+
+// Duration returns how long the span took.
+func (data SpanData) Duration() time.Duration {
+	return data.EndTime.Sub(data.StartTime)
+}
+
+// Exporter receives each span when it ends. To send the spans to an OpenTelemetry collector, implement an Exporter
+// that converts SpanData to the span model of the SDK in use.
+type Exporter interface {
+	ExportSpan(SpanData)
+}
+
+// InMemoryExporter is an Exporter that keeps all spans in memory. It is meant for tests, so no collector is needed.
+type InMemoryExporter struct {
+	mutex sync.Mutex
+	spans []SpanData
+}
+
+// NewInMemoryExporter creates a new InMemoryExporter.
+func NewInMemoryExporter() *InMemoryExporter {
+	return &InMemoryExporter{}
+}
+
+// ExportSpan keeps the span.
+func (exporter *InMemoryExporter) ExportSpan(span SpanData) {
+	exporter.mutex.Lock()
+	defer exporter.mutex.Unlock()
+	exporter.spans = append(exporter.spans, span)
+}
+
+// Spans returns a copy of the spans exported until now, in the order they ended.
+func (exporter *InMemoryExporter) Spans() []SpanData {
+	exporter.mutex.Lock()
+	defer exporter.mutex.Unlock()
+	spans := make([]SpanData, len(exporter.spans))
+	copy(spans, exporter.spans)
+	return spans
+}
+
+// Reset discards all spans exported until now.
+func (exporter *InMemoryExporter) Reset() {
+	exporter.mutex.Lock()
+	defer exporter.mutex.Unlock()
+	exporter.spans = nil
+}
+
+// span is a span that has not ended yet.
+type span struct {
+	data SpanData
+}
+
+// end sets the end time and the status of the span, accordingly to the given error.
+func (s *span) end(err error) SpanData {
+	s.data.EndTime = time.Now()
+	if err != nil {
+		s.data.Status = StatusError
+		s.data.StatusMessage = err.Error()
+	} else {
+		s.data.Status = StatusOK
+	}
+	return s.data
+}
+
+type spanCtxKey struct{}
+
+// withSpan returns a copy of the ctx carrying the span.
+func withSpan(ctx context.Context, s *span) context.Context {
+	return context.WithValue(ctx, spanCtxKey{}, s)
+}
+
+// spanFromContext returns the span carried by the ctx, or nil.
+func spanFromContext(ctx context.Context) *span {
+	if ctx == nil {
+		return nil
+	}
+	s, _ := ctx.Value(spanCtxKey{}).(*span)
+	return s
+}
+
+// TraceIDFromContext returns the TraceID of the span carried by the ctx given to the services by the
+// services.Runner (check Reporter). It returns false if there is none.
+func TraceIDFromContext(ctx context.Context) (TraceID, bool) {
+	s := spanFromContext(ctx)
+	if s == nil {
+		return TraceID{}, false
+	}
+	return s.data.TraceID, true
+}
+
+func newTraceID() (id TraceID) {
+	_, _ = rand.Read(id[:])
+	return id
+}
+
+func newSpanID() (id SpanID) {
+	_, _ = rand.Read(id[:])
+	return id
+}
@@ -0,0 +1,378 @@
+// Package tracereporter implements a services.Reporter that traces the lifecycle of the services, creating
+// OpenTelemetry-style spans: a root span for each Runner.Run and Runner.Finish, and child spans for every Load, Start,
+// retry attempt, Close, Stop and Reload. The spans are given to an Exporter when they end (check InMemoryExporter for
+// tests).
+package tracereporter
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+
+	"github.com/setare/go-services"
+)
+
+// The attributes set in the spans.
+const (
+	AttrServiceName   = "services.service.name"
+	AttrServiceKind   = "services.service.kind"
+	AttrServicesCount = "services.count"
+	AttrRetryAttempt  = "services.retry.attempt"
+	AttrRetryDelay    = "services.retry.delay"
+	AttrRestartCount  = "services.restart.count"
+	AttrRestartCause  = "services.restart.cause"
+	AttrPhase         = "services.shutdown.phase"
+	AttrSignal        = "services.signal"
+	AttrPanic         = "services.panic"
+)
+
+const (
+	// KindResource is the kind of services that implement services.Resource.
+	KindResource = "resource"
+	// KindServer is the kind of services that implement services.Server.
+	KindServer = "server"
+	// KindConfigurable is the kind of services that only implement services.Configurable.
+	KindConfigurable = "configurable"
+)
+
+// spanKey identifies a span that has not ended, so it can be ended when the After counterpart of the event that
+// started it is reported. The subject is the services.ServiceKey of the service, and the parent is the span carried by
+// the ctx of the events.
+type spanKey struct {
+	operation string
+	subject   interface{}
+	attempt   int
+	parent    *span
+}
+
+// Reporter creates spans for the lifecycle events reported by a services.Runner, a services.ResourceServiceRetrier or
+// a services.ServerSupervisor.
+//
+// The Runner gives the ctx returned by BeforeRun (and BeforeFinish), which carries the root span, to the services and to
+// all the events reported until the end of the call. So, the spans of the events are children of the root span. The
+// retry attempts are children of the span of the Load, or Start, of the same service. The spans of the Close of the
+// servers are children of the span of their shutdown phase (check services.WithShutdownPhase).
+type Reporter struct {
+	exporter   Exporter
+	attributes map[string]interface{}
+
+	mutex  sync.Mutex
+	open   map[spanKey]*span
+	phases map[*span]*span
+	roots  map[*span]struct{}
+}
+
+// Option configures a Reporter created by New.
+type Option = func(*Reporter)
+
+// WithAttributes is an Option that sets attributes added to all spans (e.g. the application version).
+func WithAttributes(attributes map[string]interface{}) Option {
+	return func(reporter *Reporter) {
+		for key, value := range attributes {
+			reporter.attributes[key] = value
+		}
+	}
+}
+
+// New creates a new Reporter that gives the spans to the given exporter.
+func New(exporter Exporter, opts ...Option) *Reporter {
+	reporter := &Reporter{
+		exporter:   exporter,
+		attributes: make(map[string]interface{}),
+		open:       make(map[spanKey]*span),
+		phases:     make(map[*span]*span),
+		roots:      make(map[*span]struct{}),
+	}
+	for _, opt := range opts {
+		opt(reporter)
+	}
+	return reporter
+}
+
+// newSpan creates a span, child of the given parent (when not nil).
+func (reporter *Reporter) newSpan(name string, parent *span, attributes map[string]interface{}) *span {
+	s := &span{
+		data: SpanData{
+			Name:       name,
+			SpanID:     newSpanID(),
+			StartTime:  time.Now(),
+			Attributes: make(map[string]interface{}, len(reporter.attributes)+len(attributes)),
+		},
+	}
+	if parent != nil {
+		s.data.TraceID = parent.data.TraceID
+		s.data.ParentSpanID = parent.data.SpanID
+	} else {
+		s.data.TraceID = newTraceID()
+	}
+	for key, value := range reporter.attributes {
+		s.data.Attributes[key] = value
+	}
+	for key, value := range attributes {
+		s.data.Attributes[key] = value
+	}
+	return s
+}
+
+// start creates a span for the operation of the given service, keeping it open until end is called with the same
+// operation, service and ctx.
+func (reporter *Reporter) start(ctx context.Context, operation string, service services.Service) {
+	ctxSpan := spanFromContext(ctx)
+
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+
+	parent := ctxSpan
+	if phase, ok := reporter.phases[ctxSpan]; ok && operation == "close" {
+		parent = phase
+	}
+	s := reporter.newSpan(spanName(operation, service), parent, serviceAttributes(service))
+	reporter.open[spanKey{operation, services.ServiceKey(service), 0, ctxSpan}] = s
+}
+
+// end ends the span started for the operation of the given service. It returns false if there is none.
+func (reporter *Reporter) end(ctx context.Context, operation string, service services.Service, err error) bool {
+	key := spanKey{operation, services.ServiceKey(service), 0, spanFromContext(ctx)}
+
+	reporter.mutex.Lock()
+	s, ok := reporter.open[key]
+	delete(reporter.open, key)
+	reporter.mutex.Unlock()
+
+	if ok {
+		reporter.exporter.ExportSpan(s.end(err))
+	}
+	return ok
+}
+
+// BeforeRun starts the root span of the Runner.Run, which is carried by the returned ctx. If the given ctx already
+// carries a span, the new span is its child.
+func (reporter *Reporter) BeforeRun(ctx context.Context, ss []services.Service) context.Context {
+	return reporter.startRoot(ctx, "Run", map[string]interface{}{
+		AttrServicesCount: len(ss),
+	})
+}
+
+// AfterRun ends the root span of the Runner.Run. Stopping by a signal, or by the ctx, is not an error.
+func (reporter *Reporter) AfterRun(ctx context.Context, err error) {
+	reporter.endRoot(ctx, err)
+}
+
+// BeforeFinish starts the root span of the Runner.Finish, which is carried by the returned ctx.
+func (reporter *Reporter) BeforeFinish(ctx context.Context) context.Context {
+	return reporter.startRoot(ctx, "Finish", nil)
+}
+
+// AfterFinish ends the root span of the Runner.Finish.
+func (reporter *Reporter) AfterFinish(ctx context.Context, err error) {
+	reporter.endRoot(ctx, err)
+}
+
+func (reporter *Reporter) startRoot(ctx context.Context, name string, attributes map[string]interface{}) context.Context {
+	s := reporter.newSpan(name, spanFromContext(ctx), attributes)
+
+	reporter.mutex.Lock()
+	reporter.roots[s] = struct{}{}
+	reporter.mutex.Unlock()
+
+	return withSpan(ctx, s)
+}
+
+func (reporter *Reporter) endRoot(ctx context.Context, err error) {
+	s := spanFromContext(ctx)
+	if s == nil {
+		return
+	}
+
+	reporter.mutex.Lock()
+	delete(reporter.roots, s)
+	delete(reporter.phases, s)
+	reporter.mutex.Unlock()
+
+	if services.ExitCode(err) != services.ExitFailure {
+		err = nil
+	}
+	reporter.exporter.ExportSpan(s.end(err))
+}
+
+// BeforeStart starts the span of the Start (or Listen) of the service.
+func (reporter *Reporter) BeforeStart(ctx context.Context, service services.Service) {
+	reporter.start(ctx, "start", service)
+}
+
+// AfterStart ends the span of the Start (or Listen) of the service.
+func (reporter *Reporter) AfterStart(ctx context.Context, service services.Service, err error) {
+	reporter.end(ctx, "start", service, err)
+}
+
+// BeforeStop starts the span of the Stop (or Close) of the service.
+func (reporter *Reporter) BeforeStop(ctx context.Context, service services.Service) {
+	reporter.start(ctx, stopOperation(service), service)
+}
+
+// AfterStop ends the span of the Stop (or Close) of the service.
+func (reporter *Reporter) AfterStop(ctx context.Context, service services.Service, err error) {
+	reporter.end(ctx, stopOperation(service), service, err)
+}
+
+// stopOperation returns the operation that stops the service: a Server is closed, a Resource is stopped.
+func stopOperation(service services.Service) string {
+	if _, ok := service.(services.Server); ok {
+		return "close"
+	}
+	return "stop"
+}
+
+// BeforeLoad starts the span of the Load of the service.
+func (reporter *Reporter) BeforeLoad(ctx context.Context, configurable services.Configurable) {
+	service, _ := configurable.(services.Service)
+	reporter.start(ctx, "load", service)
+}
+
+// AfterLoad ends the span of the Load of the service.
+func (reporter *Reporter) AfterLoad(ctx context.Context, configurable services.Configurable, err error) {
+	service, _ := configurable.(services.Service)
+	reporter.end(ctx, "load", service, err)
+}
+
+// BeforeReload starts the span of the Reload of the service.
+func (reporter *Reporter) BeforeReload(ctx context.Context, service services.Service) {
+	reporter.start(ctx, "reload", service)
+}
+
+// AfterReload ends the span of the Reload of the service.
+func (reporter *Reporter) AfterReload(ctx context.Context, service services.Service, err error) {
+	reporter.end(ctx, "reload", service, err)
+}
+
+// BeforeRetry starts the span of the attempt, child of the span of the Load, or Start, of the service with the same
+// name.
+func (reporter *Reporter) BeforeRetry(ctx context.Context, service services.Service, attempt int) {
+	ctxSpan := spanFromContext(ctx)
+	name := service.Name()
+
+	reporter.mutex.Lock()
+	parent := ctxSpan
+	for key, s := range reporter.open {
+		if key.parent != ctxSpan || (key.operation != "start" && key.operation != "load") {
+			continue
+		}
+		if subject, ok := key.subject.(services.Service); ok && subject != nil && subject.Name() == name {
+			parent = s
+			break
+		}
+	}
+	reporter.mutex.Unlock()
+
+	attributes := serviceAttributes(service)
+	attributes[AttrRetryAttempt] = attempt
+	s := reporter.newSpan(spanName("attempt", service), parent, attributes)
+
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+	reporter.open[spanKey{"attempt", services.ServiceKey(service), attempt, ctxSpan}] = s
+}
+
+// AfterRetry ends the span of the attempt. When there is a next attempt, the delay before it is set as attribute.
+func (reporter *Reporter) AfterRetry(ctx context.Context, service services.Service, attempt int, err error, delay time.Duration) {
+	key := spanKey{"attempt", services.ServiceKey(service), attempt, spanFromContext(ctx)}
+
+	reporter.mutex.Lock()
+	s, ok := reporter.open[key]
+	delete(reporter.open, key)
+	reporter.mutex.Unlock()
+	if !ok {
+		return
+	}
+
+	if delay != backoff.Stop {
+		s.data.Attributes[AttrRetryDelay] = delay.String()
+	}
+	reporter.exporter.ExportSpan(s.end(err))
+}
+
+// BeforeRestart creates a span, with no duration, for the restart of a Server by a services.ServerSupervisor.
+func (reporter *Reporter) BeforeRestart(ctx context.Context, service services.Service, restarts int, err error) {
+	attributes := serviceAttributes(service)
+	attributes[AttrRestartCount] = restarts
+	if err != nil {
+		attributes[AttrRestartCause] = err.Error()
+	}
+	s := reporter.newSpan(spanName("restart", service), spanFromContext(ctx), attributes)
+	reporter.exporter.ExportSpan(s.end(nil))
+}
+
+// BeforeShutdownPhase starts the span of the shutdown phase.
+func (reporter *Reporter) BeforeShutdownPhase(ctx context.Context, phase string) {
+	parent := spanFromContext(ctx)
+	s := reporter.newSpan(fmt.Sprintf("Shutdown phase %s", phase), parent, map[string]interface{}{
+		AttrPhase: phase,
+	})
+
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+	reporter.phases[parent] = s
+}
+
+// AfterShutdownPhase ends the span of the shutdown phase.
+func (reporter *Reporter) AfterShutdownPhase(ctx context.Context, _ string, err error) {
+	parent := spanFromContext(ctx)
+
+	reporter.mutex.Lock()
+	s, ok := reporter.phases[parent]
+	delete(reporter.phases, parent)
+	reporter.mutex.Unlock()
+
+	if ok {
+		reporter.exporter.ExportSpan(s.end(err))
+	}
+}
+
+// PanicRecovered marks the span of the operation that panicked, if there is one.
+func (reporter *Reporter) PanicRecovered(ctx context.Context, err *services.PanicError) {
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+	if s, ok := reporter.open[spanKey{err.Operation, services.ServiceKey(err.Service), 0, spanFromContext(ctx)}]; ok {
+		s.data.Attributes[AttrPanic] = fmt.Sprint(err.Value)
+	}
+}
+
+// SignalReceived sets the signal as attribute of the root spans that have not ended.
+func (reporter *Reporter) SignalReceived(sig os.Signal) {
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+	for s := range reporter.roots {
+		s.data.Attributes[AttrSignal] = sig.String()
+	}
+}
+
+// spanName returns the name of the span of the operation of the service (e.g. "Start Database").
+func spanName(operation string, service services.Service) string {
+	name := "<nil>"
+	if service != nil {
+		name = service.Name()
+	}
+	return fmt.Sprintf("%s%s %s", strings.ToUpper(operation[:1]), operation[1:], name)
+}
+
+func serviceAttributes(service services.Service) map[string]interface{} {
+	attributes := make(map[string]interface{}, 3)
+	if service == nil {
+		return attributes
+	}
+	attributes[AttrServiceName] = service.Name()
+	switch service.(type) {
+	case services.Resource:
+		attributes[AttrServiceKind] = KindResource
+	case services.Server:
+		attributes[AttrServiceKind] = KindServer
+	case services.Configurable:
+		attributes[AttrServiceKind] = KindConfigurable
+	}
+	return attributes
+}
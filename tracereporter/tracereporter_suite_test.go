@@ -0,0 +1,13 @@
+package tracereporter_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestTraceReporter(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Trace Reporter Tests")
+}
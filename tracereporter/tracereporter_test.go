@@ -0,0 +1,230 @@
+package tracereporter_test
+
+import (
+	"context"
+	"errors"
+	"syscall"
+
+	"github.com/cenkalti/backoff/v4"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/setare/go-services"
+	"github.com/setare/go-services/servicestest"
+	"github.com/setare/go-services/tracereporter"
+)
+
+// tracedResource is a services.Resource that keeps the trace of the ctx given to its Start.
+type tracedResource struct {
+	*servicestest.Resource
+	traceID tracereporter.TraceID
+}
+
+func (r *tracedResource) Start(ctx context.Context) error {
+	r.traceID, _ = tracereporter.TraceIDFromContext(ctx)
+	return r.Resource.Start(ctx)
+}
+
+// taggedResource is a services.Resource whose type is not comparable, since it holds a slice.
+type taggedResource struct {
+	name string
+	tags []string
+}
+
+func (r taggedResource) Name() string                { return r.name }
+func (r taggedResource) Start(context.Context) error { return nil }
+func (r taggedResource) Stop(context.Context) error  { return nil }
+
+// spansByName indexes the spans by their names.
+func spansByName(spans []tracereporter.SpanData) map[string]tracereporter.SpanData {
+	result := make(map[string]tracereporter.SpanData, len(spans))
+	for _, span := range spans {
+		Expect(result).ToNot(HaveKey(span.Name), "duplicated span %s", span.Name)
+		result[span.Name] = span
+	}
+	return result
+}
+
+func names(spans []tracereporter.SpanData) []string {
+	result := make([]string, len(spans))
+	for idx, span := range spans {
+		result[idx] = span.Name
+	}
+	return result
+}
+
+var _ = Describe("Reporter", func() {
+	var _ services.RunReporter = &tracereporter.Reporter{}
+	var _ services.RetrierReporter = &tracereporter.Reporter{}
+	var _ services.AttemptReporter = &tracereporter.Reporter{}
+	var _ services.SupervisorReporter = &tracereporter.Reporter{}
+	var _ services.PhaseReporter = &tracereporter.Reporter{}
+	var _ services.PanicReporter = &tracereporter.Reporter{}
+	var _ services.ReloadReporter = &tracereporter.Reporter{}
+
+	var exporter *tracereporter.InMemoryExporter
+
+	BeforeEach(func() {
+		exporter = tracereporter.NewInMemoryExporter()
+	})
+
+	It("should trace the Run and the Finish", func() {
+		ctx, cancelFunc := context.WithCancel(context.TODO())
+		defer cancelFunc()
+
+		config := servicestest.NewConfigurable("Config")
+		database := &tracedResource{Resource: servicestest.NewResource("Database")}
+		server := servicestest.NewServer("Server")
+
+		reporter := tracereporter.New(exporter, tracereporter.WithAttributes(map[string]interface{}{
+			"version": "1.0.0",
+		}))
+		runner := services.NewRunner(services.WithReporter(reporter))
+
+		Expect(runner.Run(ctx, config, database)).To(Succeed())
+
+		ran := make(chan error)
+		go func() {
+			ran <- runner.Run(ctx, server)
+		}()
+		Eventually(server.Ready()).Should(BeClosed())
+		Eventually(func() []string {
+			return names(exporter.Spans())
+		}).Should(ContainElement("Start Server"))
+		cancelFunc()
+		Eventually(ran).Should(Receive(MatchError(context.Canceled)))
+		Expect(runner.Finish(context.TODO())).To(Succeed())
+
+		spans := exporter.Spans()
+		Expect(names(spans)).To(Equal([]string{
+			"Load Config", "Start Config", "Start Database", "Run",
+			"Start Server", "Close Server", "Shutdown phase default", "Run",
+			"Stop Database", "Stop Config", "Finish",
+		}))
+
+		firstRun, secondRun, finish := spans[3], spans[7], spans[10]
+		for _, root := range []tracereporter.SpanData{firstRun, secondRun, finish} {
+			Expect(root.ParentSpanID.IsValid()).To(BeFalse())
+			Expect(root.Status).To(Equal(tracereporter.StatusOK))
+			Expect(root.Attributes).To(HaveKeyWithValue("version", "1.0.0"))
+		}
+		Expect(firstRun.TraceID).ToNot(Equal(secondRun.TraceID))
+		Expect(firstRun.Attributes).To(HaveKeyWithValue(tracereporter.AttrServicesCount, 2))
+		Expect(database.traceID).To(Equal(firstRun.TraceID))
+
+		for _, span := range spans[:3] {
+			Expect(span.TraceID).To(Equal(firstRun.TraceID))
+			Expect(span.ParentSpanID).To(Equal(firstRun.SpanID))
+			Expect(span.Status).To(Equal(tracereporter.StatusOK))
+			Expect(span.EndTime).To(BeTemporally(">=", span.StartTime))
+		}
+		Expect(spans[0].Attributes).To(HaveKeyWithValue(tracereporter.AttrServiceName, "Config"))
+		Expect(spans[0].Attributes).To(HaveKeyWithValue(tracereporter.AttrServiceKind, tracereporter.KindResource))
+
+		startServer, closeServer, phase := spans[4], spans[5], spans[6]
+		Expect(startServer.ParentSpanID).To(Equal(secondRun.SpanID))
+		Expect(startServer.Attributes).To(HaveKeyWithValue(tracereporter.AttrServiceKind, tracereporter.KindServer))
+		Expect(phase.ParentSpanID).To(Equal(secondRun.SpanID))
+		Expect(phase.Attributes).To(HaveKeyWithValue(tracereporter.AttrPhase, services.DefaultShutdownPhase))
+		Expect(closeServer.ParentSpanID).To(Equal(phase.SpanID))
+		Expect(closeServer.Attributes).To(HaveKeyWithValue(tracereporter.AttrServiceKind, tracereporter.KindServer))
+		Expect(closeServer.StartTime).To(BeTemporally(">=", phase.StartTime))
+		Expect(closeServer.EndTime).To(BeTemporally("<=", phase.EndTime))
+
+		for _, span := range spans[8:10] {
+			Expect(span.TraceID).To(Equal(finish.TraceID))
+			Expect(span.ParentSpanID).To(Equal(finish.SpanID))
+		}
+	})
+
+	It("should set the error status of failed operations", func() {
+		ctx := context.TODO()
+
+		wantErr := errors.New("connection refused")
+		database := servicestest.NewResource("Database").OnStart(servicestest.Fail(wantErr))
+
+		runner := services.NewRunner(services.WithReporter(tracereporter.New(exporter)))
+		Expect(runner.Run(ctx, database)).To(MatchError(wantErr))
+
+		spans := spansByName(exporter.Spans())
+		Expect(spans["Start Database"].Status).To(Equal(tracereporter.StatusError))
+		Expect(spans["Start Database"].StatusMessage).To(Equal("connection refused"))
+		Expect(spans["Run"].Status).To(Equal(tracereporter.StatusError))
+		Expect(spans["Run"].Status.String()).To(Equal("Error"))
+	})
+
+	It("should trace the retry attempts as children of the Start", func() {
+		ctx := context.TODO()
+
+		wantErr := errors.New("connection refused")
+		database := servicestest.NewResource("Database").OnStart(servicestest.Fail(wantErr), servicestest.Succeed())
+
+		reporter := tracereporter.New(exporter)
+		retrier := services.Retrier().Backoff(&backoff.ZeroBackOff{}).Reporter(reporter).Build(database)
+
+		runner := services.NewRunner(services.WithReporter(reporter))
+		Expect(runner.Run(ctx, retrier)).To(Succeed())
+
+		spans := exporter.Spans()
+		Expect(names(spans)).To(Equal([]string{
+			"Attempt Database", "Attempt Database", "Start Database", "Run",
+		}))
+		start := spans[2]
+		Expect(spans[0].ParentSpanID).To(Equal(start.SpanID))
+		Expect(spans[0].Status).To(Equal(tracereporter.StatusError))
+		Expect(spans[0].Attributes).To(HaveKeyWithValue(tracereporter.AttrRetryAttempt, 1))
+		Expect(spans[0].Attributes).To(HaveKeyWithValue(tracereporter.AttrRetryDelay, "0s"))
+		Expect(spans[1].ParentSpanID).To(Equal(start.SpanID))
+		Expect(spans[1].Status).To(Equal(tracereporter.StatusOK))
+		Expect(spans[1].Attributes).To(HaveKeyWithValue(tracereporter.AttrRetryAttempt, 2))
+		Expect(spans[1].Attributes).ToNot(HaveKey(tracereporter.AttrRetryDelay))
+	})
+
+	It("should mark the spans of the operations that panicked", func() {
+		ctx := context.TODO()
+
+		database := servicestest.NewResource("Database").OnStop(servicestest.Panic("boom"))
+
+		reporter := tracereporter.New(exporter)
+		runner := services.NewRunner(services.WithReporter(reporter))
+		Expect(runner.Run(ctx, database)).To(Succeed())
+		Expect(runner.Finish(ctx)).To(MatchError(services.ErrPanic))
+
+		spans := spansByName(exporter.Spans())
+		Expect(spans["Stop Database"].Status).To(Equal(tracereporter.StatusError))
+		Expect(spans["Stop Database"].Attributes).To(HaveKeyWithValue(tracereporter.AttrPanic, "boom"))
+		Expect(spans["Finish"].Status).To(Equal(tracereporter.StatusError))
+	})
+
+	It("should trace the services whose types are not comparable", func() {
+		ctx := context.TODO()
+		reporter := tracereporter.New(exporter)
+
+		serviceA := taggedResource{name: "Database", tags: []string{"db"}}
+		reporter.BeforeStart(ctx, serviceA)
+		reporter.AfterStart(ctx, serviceA, nil)
+
+		Expect(names(exporter.Spans())).To(Equal([]string{"Start Database"}))
+	})
+
+	It("should be a child of the span carried by the ctx", func() {
+		reporter := tracereporter.New(exporter)
+
+		ctx := reporter.BeforeRun(context.TODO(), nil)
+		reporter.SignalReceived(syscall.SIGTERM)
+		childCtx := reporter.BeforeFinish(ctx)
+		reporter.AfterFinish(childCtx, nil)
+		reporter.AfterRun(ctx, &services.SignalError{Signal: syscall.SIGTERM})
+
+		spans := exporter.Spans()
+		Expect(names(spans)).To(Equal([]string{"Finish", "Run"}))
+		Expect(spans[0].TraceID).To(Equal(spans[1].TraceID))
+		Expect(spans[0].ParentSpanID).To(Equal(spans[1].SpanID))
+		Expect(spans[1].Status).To(Equal(tracereporter.StatusOK))
+		Expect(spans[1].Attributes).To(HaveKeyWithValue(tracereporter.AttrSignal, syscall.SIGTERM.String()))
+		Expect(spans[0].Attributes).ToNot(HaveKey(tracereporter.AttrSignal))
+
+		exporter.Reset()
+		Expect(exporter.Spans()).To(BeEmpty())
+	})
+})